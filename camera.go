@@ -0,0 +1,310 @@
+package glad
+
+import (
+	"math"
+
+	"github.com/akiross/go-glad/mathx"
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+// Camera produces the matrices to transform world coordinates to clip space
+// Update reads the input state of the window (it does not install callbacks,
+// which are left to the application) and the size of its framebuffer, so
+// that the aspect ratio follows the window when resized. Scroll wheel
+// events are not available as state: forward them from the scroll callback
+// to the Scroll method of the camera
+type Camera interface {
+	View() mathx.Mat4
+	Projection() mathx.Mat4
+	Update(win *glfw.Window, dt float32)
+	Scroll(offset float64)
+}
+
+// cameraBase holds the state shared by the cameras
+type cameraBase struct {
+	aspect     float32 // Width / height of the framebuffer
+	winW, winH int     // Size of the window in screen coordinates
+	curX, curY float64 // Cursor position at the previous update
+	tracking   bool    // curX and curY are valid
+}
+
+// updateInput reads the sizes of the window and returns the movement of the
+// cursor since the previous update
+func (cb *cameraBase) updateInput(win *glfw.Window) (dx, dy float32) {
+	fw, fh := win.GetFramebufferSize()
+	if fw > 0 && fh > 0 {
+		// A minimized window has size 0, keep the previous aspect
+		cb.aspect = float32(fw) / float32(fh)
+	}
+	cb.winW, cb.winH = win.GetSize()
+	x, y := win.GetCursorPos()
+	if cb.tracking {
+		dx, dy = float32(x-cb.curX), float32(y-cb.curY)
+	}
+	cb.curX, cb.curY, cb.tracking = x, y, true
+	return
+}
+
+// Aspect returns the aspect ratio (width / height) used for the projection
+func (cb *cameraBase) Aspect() float32 {
+	if cb.aspect == 0 {
+		return 1
+	}
+	return cb.aspect
+}
+
+// SetAspect sets the aspect ratio, it is overwritten by Update
+func (cb *cameraBase) SetAspect(aspect float32) {
+	cb.aspect = aspect
+}
+
+// pressed reports if a mouse button is held down
+func pressed(win *glfw.Window, button glfw.MouseButton) bool {
+	return win.GetMouseButton(button) == glfw.Press
+}
+
+// maxPitch keeps the cameras from looking exactly up or down
+const maxPitch = math.Pi/2 - 0.001
+
+// OrbitCamera looks at a target from a distance, rotating around it
+// Drag with the left button to rotate, with the right or middle button to
+// pan, use the wheel to zoom
+type OrbitCamera struct {
+	cameraBase
+	Target      mathx.Vec3
+	Distance    float32
+	Yaw, Pitch  float32 // Rotation around the target, 0 looks toward -Z
+	FovY        float32 // Vertical field of view, in radians
+	Near, Far   float32
+	MinDistance float32
+	MaxDistance float32
+	RotateSpeed float32 // Radians per pixel
+	ZoomSpeed   float32 // Fraction of distance per step of the wheel
+}
+
+// NewOrbitCamera returns a camera looking at target from distance
+func NewOrbitCamera(target mathx.Vec3, distance float32) *OrbitCamera {
+	return &OrbitCamera{
+		Target:      target,
+		Distance:    distance,
+		FovY:        mathx.Radians(60),
+		Near:        distance / 100,
+		Far:         distance * 100,
+		MinDistance: distance / 100,
+		MaxDistance: distance * 50,
+		RotateSpeed: 0.01,
+		ZoomSpeed:   0.1,
+	}
+}
+
+// FitBounds places the target in the center of the box and sets the distance
+// to see it whole, e.g. with the Min and Max of a Mesh
+func (c *OrbitCamera) FitBounds(min, max mathx.Vec3) {
+	c.Target = min.Add(max).Mul(0.5)
+	radius := max.Sub(min).Len() / 2
+	if radius == 0 {
+		radius = 1
+	}
+	fov := c.FovY
+	if a := c.Aspect(); a < 1 {
+		// The horizontal field of view is the narrowest
+		fov = 2 * float32(math.Atan(math.Tan(float64(fov)/2)*float64(a)))
+	}
+	c.Distance = radius / float32(math.Sin(float64(fov)/2))
+	c.Near, c.Far = c.Distance/100, c.Distance+radius*10
+	c.MinDistance, c.MaxDistance = radius/10, c.Distance*20
+}
+
+// Eye returns the position of the camera
+func (c *OrbitCamera) Eye() mathx.Vec3 {
+	sy, cy := math.Sincos(float64(c.Yaw))
+	sp, cp := math.Sincos(float64(c.Pitch))
+	dir := mathx.Vec3{float32(-sy * cp), float32(sp), float32(cy * cp)}
+	return c.Target.Add(dir.Mul(c.Distance))
+}
+
+func (c *OrbitCamera) View() mathx.Mat4 {
+	return mathx.LookAt(c.Eye(), c.Target, mathx.Vec3{0, 1, 0})
+}
+
+func (c *OrbitCamera) Projection() mathx.Mat4 {
+	return mathx.Perspective(c.FovY, c.Aspect(), c.Near, c.Far)
+}
+
+// Update rotates or pans the camera following the mouse
+func (c *OrbitCamera) Update(win *glfw.Window, dt float32) {
+	dx, dy := c.updateInput(win)
+	switch {
+	case pressed(win, glfw.MouseButtonLeft):
+		c.Yaw -= dx * c.RotateSpeed
+		c.Pitch = mathx.Clamp(c.Pitch+dy*c.RotateSpeed, -maxPitch, maxPitch)
+	case pressed(win, glfw.MouseButtonRight), pressed(win, glfw.MouseButtonMiddle):
+		// Move the target so that it follows the cursor
+		if c.winH == 0 {
+			return
+		}
+		view := c.View()
+		right := mathx.Vec3{view[0], view[4], view[8]}
+		up := mathx.Vec3{view[1], view[5], view[9]}
+		perPixel := 2 * c.Distance * float32(math.Tan(float64(c.FovY)/2)) / float32(c.winH)
+		c.Target = c.Target.Add(right.Mul(-dx * perPixel)).Add(up.Mul(dy * perPixel))
+	}
+}
+
+// Scroll moves the camera toward the target for positive offsets
+func (c *OrbitCamera) Scroll(offset float64) {
+	c.Distance *= float32(math.Pow(float64(1-c.ZoomSpeed), offset))
+	c.Distance = mathx.Clamp(c.Distance, c.MinDistance, c.MaxDistance)
+}
+
+// FlyCamera moves freely: W, A, S, D move on the horizontal plane, E and Q
+// move up and down, holding shift moves faster. Drag with the right button to
+// look around, or disable the cursor (glfw.CursorDisabled) to always follow it
+type FlyCamera struct {
+	cameraBase
+	Position    mathx.Vec3
+	Yaw, Pitch  float32 // 0 looks toward -Z, positive yaw turns left
+	FovY        float32 // Vertical field of view, in radians
+	Near, Far   float32
+	Speed       float32 // Units per second
+	Sensitivity float32 // Radians per pixel
+}
+
+// NewFlyCamera returns a camera in position looking toward -Z
+func NewFlyCamera(position mathx.Vec3) *FlyCamera {
+	return &FlyCamera{
+		Position:    position,
+		FovY:        mathx.Radians(60),
+		Near:        0.1,
+		Far:         1000,
+		Speed:       5,
+		Sensitivity: 0.003,
+	}
+}
+
+// Forward returns the direction the camera is looking toward
+func (c *FlyCamera) Forward() mathx.Vec3 {
+	sy, cy := math.Sincos(float64(c.Yaw))
+	sp, cp := math.Sincos(float64(c.Pitch))
+	return mathx.Vec3{float32(-sy * cp), float32(sp), float32(-cy * cp)}
+}
+
+// LookAt turns the camera toward a point
+func (c *FlyCamera) LookAt(target mathx.Vec3) {
+	d := target.Sub(c.Position).Normalize()
+	c.Pitch = float32(math.Asin(float64(mathx.Clamp(d[1], -1, 1))))
+	c.Yaw = float32(math.Atan2(float64(-d[0]), float64(-d[2])))
+}
+
+func (c *FlyCamera) View() mathx.Mat4 {
+	return mathx.LookAt(c.Position, c.Position.Add(c.Forward()), mathx.Vec3{0, 1, 0})
+}
+
+func (c *FlyCamera) Projection() mathx.Mat4 {
+	return mathx.Perspective(c.FovY, c.Aspect(), c.Near, c.Far)
+}
+
+// Update moves the camera with the keyboard and turns it with the mouse
+func (c *FlyCamera) Update(win *glfw.Window, dt float32) {
+	dx, dy := c.updateInput(win)
+	if win.GetInputMode(glfw.CursorMode) == glfw.CursorDisabled || pressed(win, glfw.MouseButtonRight) {
+		c.Yaw -= dx * c.Sensitivity
+		c.Pitch = mathx.Clamp(c.Pitch-dy*c.Sensitivity, -maxPitch, maxPitch)
+	}
+	sy, cy := math.Sincos(float64(c.Yaw))
+	forward := mathx.Vec3{float32(-sy), 0, float32(-cy)}
+	right := mathx.Vec3{float32(cy), 0, float32(-sy)}
+	var move mathx.Vec3
+	keys := []struct {
+		key glfw.Key
+		dir mathx.Vec3
+	}{
+		{glfw.KeyW, forward}, {glfw.KeyS, forward.Mul(-1)},
+		{glfw.KeyD, right}, {glfw.KeyA, right.Mul(-1)},
+		{glfw.KeyE, mathx.Vec3{0, 1, 0}}, {glfw.KeyQ, mathx.Vec3{0, -1, 0}},
+	}
+	for _, k := range keys {
+		if win.GetKey(k.key) == glfw.Press {
+			move = move.Add(k.dir)
+		}
+	}
+	speed := c.Speed
+	if win.GetKey(glfw.KeyLeftShift) == glfw.Press || win.GetKey(glfw.KeyRightShift) == glfw.Press {
+		speed *= 4
+	}
+	c.Position = c.Position.Add(move.Normalize().Mul(speed * dt))
+}
+
+// Scroll changes the speed of the camera
+func (c *FlyCamera) Scroll(offset float64) {
+	c.Speed *= float32(math.Pow(1.2, offset))
+}
+
+// Camera2D shows a rectangle of the XY plane: drag with the right or middle
+// button to pan, use the wheel to zoom around the cursor
+type Camera2D struct {
+	cameraBase
+	Center    mathx.Vec2 // World position in the center of the view
+	Height    float32    // Height of the view in world units, width follows the aspect
+	MinHeight float32
+	MaxHeight float32
+	ZoomSpeed float32 // Fraction of height per step of the wheel
+}
+
+// NewCamera2D returns a camera showing height world units vertically
+// For instance, NewCamera2D(mathx.Vec2{}, 2) on a square window shows the
+// region from -1 to 1, where world coordinates match clip space
+func NewCamera2D(center mathx.Vec2, height float32) *Camera2D {
+	return &Camera2D{
+		Center:    center,
+		Height:    height,
+		MinHeight: height / 100,
+		MaxHeight: height * 100,
+		ZoomSpeed: 0.1,
+	}
+}
+
+func (c *Camera2D) View() mathx.Mat4 {
+	return mathx.Ident4()
+}
+
+func (c *Camera2D) Projection() mathx.Mat4 {
+	hw, hh := c.Height*c.Aspect()/2, c.Height/2
+	return mathx.Ortho(c.Center[0]-hw, c.Center[0]+hw, c.Center[1]-hh, c.Center[1]+hh, -1, 1)
+}
+
+// ScreenToWorld converts a position in screen coordinates, as returned by
+// GetCursorPos or passed to cursor callbacks, to world coordinates
+func (c *Camera2D) ScreenToWorld(x, y float64) mathx.Vec2 {
+	if c.winW == 0 || c.winH == 0 {
+		return c.Center
+	}
+	nx, ny := float32(x)/float32(c.winW)-0.5, 0.5-float32(y)/float32(c.winH)
+	return mathx.Vec2{c.Center[0] + nx*c.Height*c.Aspect(), c.Center[1] + ny*c.Height}
+}
+
+// WorldToScreen converts world coordinates to screen coordinates
+func (c *Camera2D) WorldToScreen(p mathx.Vec2) (x, y float64) {
+	nx := (p[0]-c.Center[0])/(c.Height*c.Aspect()) + 0.5
+	ny := 0.5 - (p[1]-c.Center[1])/c.Height
+	return float64(nx * float32(c.winW)), float64(ny * float32(c.winH))
+}
+
+// Update pans the camera following the mouse
+func (c *Camera2D) Update(win *glfw.Window, dt float32) {
+	dx, dy := c.updateInput(win)
+	if c.winH > 0 && (pressed(win, glfw.MouseButtonRight) || pressed(win, glfw.MouseButtonMiddle)) {
+		perPixel := c.Height / float32(c.winH)
+		c.Center = c.Center.Add(mathx.Vec2{-dx * perPixel, dy * perPixel})
+	}
+}
+
+// Scroll zooms in for positive offsets, keeping the point under the cursor
+// (at the previous Update) still
+func (c *Camera2D) Scroll(offset float64) {
+	before := c.ScreenToWorld(c.curX, c.curY)
+	c.Height *= float32(math.Pow(float64(1-c.ZoomSpeed), offset))
+	c.Height = mathx.Clamp(c.Height, c.MinHeight, c.MaxHeight)
+	after := c.ScreenToWorld(c.curX, c.curY)
+	c.Center = c.Center.Add(before.Sub(after))
+}
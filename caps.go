@@ -0,0 +1,188 @@
+package glad
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+// Capabilities describes what the current context supports
+type Capabilities struct {
+	Version                          [2]int // Major and minor version of GL
+	GLSLVersion                      [2]int // Major and minor version of GLSL, e.g. {4, 50}
+	VersionString, GLSLVersionString string
+	Vendor, Renderer                 string
+	Extensions                       map[string]bool
+	Limits                           Limits
+}
+
+// Limits holds common implementation limits of the context
+type Limits struct {
+	MaxTextureSize               int32
+	Max3DTextureSize             int32
+	MaxCubeMapTextureSize        int32
+	MaxArrayTextureLayers        int32
+	MaxTextureImageUnits         int32   // Per fragment shader
+	MaxCombinedTextureImageUnits int32   // In all stages
+	MaxTextureMaxAnisotropy      float32 // 0 if anisotropic filtering is not supported
+	MaxVertexAttribs             int32
+	MaxVertexAttribBindings      int32
+	MaxUniformBlockSize          int32 // In bytes
+	MaxUniformBufferBindings     int32
+	UniformBufferOffsetAlignment int32
+	MaxShaderStorageBlockSize    int64 // In bytes
+	MaxShaderStorageBindings     int32
+	ShaderStorageOffsetAlignment int32
+	MaxComputeWorkGroupCount     [3]int32
+	MaxComputeWorkGroupSize      [3]int32
+	MaxComputeInvocations        int32 // Per work group
+	MaxSamples                   int32
+	MaxColorAttachments          int32
+	MaxDrawBuffers               int32
+	MaxViewportDims              [2]int32
+}
+
+// capsCache holds the capabilities of each context, identified by its window
+// Contexts on other threads (e.g. a Loader) query it too, guard with capsMu
+var (
+	capsCache = make(map[*glfw.Window]*Capabilities)
+	capsMu    sync.Mutex
+)
+
+// Caps returns the capabilities of the current context
+// They are queried once per context, the result must not be modified
+func Caps() *Capabilities {
+	ctx := glfw.GetCurrentContext()
+	capsMu.Lock()
+	c, ok := capsCache[ctx]
+	capsMu.Unlock()
+	if ok {
+		return c
+	}
+	c = queryCaps()
+	if ctx != nil {
+		capsMu.Lock()
+		capsCache[ctx] = c
+		capsMu.Unlock()
+	}
+	return c
+}
+
+// forgetCaps removes the capabilities of the context of a destroyed window
+func forgetCaps(win *glfw.Window) {
+	capsMu.Lock()
+	delete(capsCache, win)
+	capsMu.Unlock()
+}
+
+// RequireVersion returns an error if the current context is older than
+// version maj.min
+func RequireVersion(maj, min int) error {
+	return Caps().RequireVersion(maj, min)
+}
+
+// RequireExtensions returns an error listing the extensions not supported
+// by the current context
+func RequireExtensions(names ...string) error {
+	return Caps().RequireExtensions(names...)
+}
+
+// AtLeast reports if the version of the context is maj.min or newer
+func (c *Capabilities) AtLeast(maj, min int) bool {
+	return c.Version[0] > maj || (c.Version[0] == maj && c.Version[1] >= min)
+}
+
+// HasExtension reports if the extension (e.g. "GL_ARB_bindless_texture") is supported
+func (c *Capabilities) HasExtension(name string) bool {
+	return c.Extensions[name]
+}
+
+// RequireVersion returns an error if the context is older than maj.min
+func (c *Capabilities) RequireVersion(maj, min int) error {
+	if !c.AtLeast(maj, min) {
+		return fmt.Errorf("OpenGL %d.%d required, the context is %d.%d (%s on %s)",
+			maj, min, c.Version[0], c.Version[1], c.Renderer, c.Vendor)
+	}
+	return nil
+}
+
+// RequireExtensions returns an error listing the unsupported extensions
+func (c *Capabilities) RequireExtensions(names ...string) error {
+	var missing []string
+	for _, n := range names {
+		if !c.Extensions[n] {
+			missing = append(missing, n)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing OpenGL extensions on %s: %s", c.Renderer, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// ExtensionList returns the supported extensions, sorted
+func (c *Capabilities) ExtensionList() []string {
+	list := make([]string, 0, len(c.Extensions))
+	for e := range c.Extensions {
+		list = append(list, e)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// queryCaps reads the capabilities of the current context
+func queryCaps() *Capabilities {
+	var c Capabilities
+	c.VersionString = gl.GoStr(gl.GetString(gl.VERSION))
+	c.GLSLVersionString = gl.GoStr(gl.GetString(gl.SHADING_LANGUAGE_VERSION))
+	c.Vendor = gl.GoStr(gl.GetString(gl.VENDOR))
+	c.Renderer = gl.GoStr(gl.GetString(gl.RENDERER))
+	c.Version[0] = int(getInteger(gl.MAJOR_VERSION))
+	c.Version[1] = int(getInteger(gl.MINOR_VERSION))
+	fmt.Sscanf(c.GLSLVersionString, "%d.%d", &c.GLSLVersion[0], &c.GLSLVersion[1])
+
+	n := getInteger(gl.NUM_EXTENSIONS)
+	c.Extensions = make(map[string]bool, n)
+	for i := uint32(0); i < uint32(n); i++ {
+		c.Extensions[gl.GoStr(gl.GetStringi(gl.EXTENSIONS, i))] = true
+	}
+
+	l := &c.Limits
+	l.MaxTextureSize = getInteger(gl.MAX_TEXTURE_SIZE)
+	l.Max3DTextureSize = getInteger(gl.MAX_3D_TEXTURE_SIZE)
+	l.MaxCubeMapTextureSize = getInteger(gl.MAX_CUBE_MAP_TEXTURE_SIZE)
+	l.MaxArrayTextureLayers = getInteger(gl.MAX_ARRAY_TEXTURE_LAYERS)
+	l.MaxTextureImageUnits = getInteger(gl.MAX_TEXTURE_IMAGE_UNITS)
+	l.MaxCombinedTextureImageUnits = getInteger(gl.MAX_COMBINED_TEXTURE_IMAGE_UNITS)
+	if c.AtLeast(4, 6) || c.Extensions["GL_EXT_texture_filter_anisotropic"] || c.Extensions["GL_ARB_texture_filter_anisotropic"] {
+		gl.GetFloatv(gl.MAX_TEXTURE_MAX_ANISOTROPY, &l.MaxTextureMaxAnisotropy)
+	}
+	l.MaxVertexAttribs = getInteger(gl.MAX_VERTEX_ATTRIBS)
+	l.MaxVertexAttribBindings = getInteger(gl.MAX_VERTEX_ATTRIB_BINDINGS)
+	l.MaxUniformBlockSize = getInteger(gl.MAX_UNIFORM_BLOCK_SIZE)
+	l.MaxUniformBufferBindings = getInteger(gl.MAX_UNIFORM_BUFFER_BINDINGS)
+	l.UniformBufferOffsetAlignment = getInteger(gl.UNIFORM_BUFFER_OFFSET_ALIGNMENT)
+	gl.GetInteger64v(gl.MAX_SHADER_STORAGE_BLOCK_SIZE, &l.MaxShaderStorageBlockSize)
+	l.MaxShaderStorageBindings = getInteger(gl.MAX_SHADER_STORAGE_BUFFER_BINDINGS)
+	l.ShaderStorageOffsetAlignment = getInteger(gl.SHADER_STORAGE_BUFFER_OFFSET_ALIGNMENT)
+	for i := uint32(0); i < 3; i++ {
+		gl.GetIntegeri_v(gl.MAX_COMPUTE_WORK_GROUP_COUNT, i, &l.MaxComputeWorkGroupCount[i])
+		gl.GetIntegeri_v(gl.MAX_COMPUTE_WORK_GROUP_SIZE, i, &l.MaxComputeWorkGroupSize[i])
+	}
+	l.MaxComputeInvocations = getInteger(gl.MAX_COMPUTE_WORK_GROUP_INVOCATIONS)
+	l.MaxSamples = getInteger(gl.MAX_SAMPLES)
+	l.MaxColorAttachments = getInteger(gl.MAX_COLOR_ATTACHMENTS)
+	l.MaxDrawBuffers = getInteger(gl.MAX_DRAW_BUFFERS)
+	gl.GetIntegerv(gl.MAX_VIEWPORT_DIMS, &l.MaxViewportDims[0])
+	return &c
+}
+
+func getInteger(pname uint32) int32 {
+	var v int32
+	gl.GetIntegerv(pname, &v)
+	return v
+}
@@ -0,0 +1,309 @@
+package glad
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// Context dispatches functions to the OS thread that owns a GL context, so
+// that any goroutine can use GL safely. The functions run in the order they
+// are submitted. Typical usage is
+//
+//	ctx := glad.NewContext()
+//	var win *glad.Window
+//	var err error
+//	ctx.Do(func() { win, err = glad.NewWindow(800, 600, "App") })
+//	// from any goroutine
+//	done := ctx.DoAsync(func() { tex.SubImage2D(img) })
+//
+// When the thread runs its own loop (e.g. glad.Run started with Do), the
+// submitted functions are executed only when the loop calls Process
+type Context struct {
+	mu      sync.Mutex
+	queue   []job
+	wake    chan struct{} // Signals that the queue is not empty
+	done    chan struct{}
+	stopped bool
+	goid    int64 // Goroutine locked to the thread, 0 if not serving yet
+}
+
+type job struct {
+	fns  []func()
+	errc chan error // Buffered, nil for nobody waiting
+}
+
+// ErrContextStopped is returned for the functions submitted to a stopped context
+var ErrContextStopped = errors.New("glad: context stopped")
+
+// PanicError is the error reported when a function run on the context
+// thread panics
+type PanicError struct {
+	Value interface{} // Value passed to panic
+	Stack string      // Stack of the panicking goroutine
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("glad: panic on the context thread: %v", e.Value)
+}
+
+// threadCheck is the context whose thread is checked by the wrappers
+var threadCheck atomic.Value
+
+// NewContext starts a dispatcher on a new goroutine locked to its OS thread
+// On macOS windows must be created on the main thread, use RunMain there
+func NewContext() *Context {
+	c := newContext()
+	started := make(chan struct{})
+	go func() {
+		runtime.LockOSThread()
+		c.setThread()
+		close(started)
+		c.serve()
+	}()
+	<-started
+	return c
+}
+
+// RunMain serves a dispatcher on the calling goroutine, which must be locked
+// to the main OS thread (e.g. with runtime.LockOSThread in init), while fn
+// runs on a new goroutine. It returns when fn returns
+func RunMain(fn func(ctx *Context)) {
+	runtime.LockOSThread()
+	c := newContext()
+	c.setThread()
+	go func() {
+		defer c.Stop()
+		fn(c)
+	}()
+	c.serve()
+}
+
+func newContext() *Context {
+	return &Context{
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+}
+
+func (c *Context) setThread() {
+	atomic.StoreInt64(&c.goid, goid())
+}
+
+// serve processes the queue until the context is stopped
+func (c *Context) serve() {
+	for {
+		select {
+		case <-c.wake:
+			c.Process()
+		case <-c.done:
+			c.Process()
+			return
+		}
+	}
+}
+
+// Process runs the functions submitted so far, it must be called on the
+// context thread. Functions submitted while processing wait for the next call
+func (c *Context) Process() {
+	c.mu.Lock()
+	queue := c.queue
+	c.queue = nil
+	c.mu.Unlock()
+	for _, j := range queue {
+		err := runJob(j.fns)
+		if j.errc != nil {
+			j.errc <- err
+			close(j.errc)
+		}
+	}
+}
+
+// runJob runs all the functions, returning the first panic as error
+func runJob(fns []func()) error {
+	var first error
+	failed := 0
+	for _, fn := range fns {
+		if err := protect(fn); err != nil {
+			if first == nil {
+				first = err
+			}
+			failed++
+		}
+	}
+	if failed > 1 {
+		return fmt.Errorf("%v (and %d more failures in batch)", first, failed-1)
+	}
+	return first
+}
+
+func protect(fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			buf := make([]byte, 8192)
+			buf = buf[:runtime.Stack(buf, false)]
+			err = &PanicError{Value: r, Stack: string(buf)}
+		}
+	}()
+	fn()
+	return nil
+}
+
+// submit adds functions to the queue
+func (c *Context) submit(fns []func(), errc chan error) {
+	c.mu.Lock()
+	if c.stopped {
+		c.mu.Unlock()
+		if errc != nil {
+			errc <- ErrContextStopped
+			close(errc)
+		}
+		return
+	}
+	c.queue = append(c.queue, job{fns, errc})
+	c.mu.Unlock()
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// OnThread reports if the caller is running on the context thread
+func (c *Context) OnThread() bool {
+	return goid() == atomic.LoadInt64(&c.goid)
+}
+
+// Do runs fn on the context thread and waits for it to complete
+// When called on the context thread fn is run directly. A panic in fn is
+// propagated to the caller as a *PanicError. If the context is stopped, fn
+// is not run and Do panics with ErrContextStopped: use DoAsync where the
+// context can be stopped concurrently, to get the error instead
+func (c *Context) Do(fn func()) {
+	if c.OnThread() {
+		fn()
+		return
+	}
+	if err := <-c.DoAsync(fn); err != nil {
+		panic(err)
+	}
+}
+
+// DoAsync submits fn to the context thread and returns immediately
+// The channel receives nil when fn completes, a *PanicError if it panics or
+// ErrContextStopped if the context is stopped before running it
+func (c *Context) DoAsync(fn func()) <-chan error {
+	errc := make(chan error, 1)
+	c.submit([]func(){fn}, errc)
+	return errc
+}
+
+// Stop stops the dispatcher after running the functions already submitted
+// Functions submitted later fail with ErrContextStopped
+func (c *Context) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.stopped {
+		c.stopped = true
+		close(c.done)
+	}
+}
+
+// stopAfter stops the dispatcher like Stop, running fn after the functions
+// already submitted. No function can be submitted between the two
+func (c *Context) stopAfter(fn func()) <-chan error {
+	errc := make(chan error, 1)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stopped {
+		errc <- ErrContextStopped
+		close(errc)
+		return errc
+	}
+	c.queue = append(c.queue, job{[]func(){fn}, errc})
+	c.stopped = true
+	close(c.done)
+	return errc
+}
+
+// SetDebug enables the debug mode: the wrappers of this package panic when
+// called off the thread of this context. Only one context can be checked at
+// a time, enabling it on another context moves the check there
+func (c *Context) SetDebug(on bool) {
+	if on {
+		threadCheck.Store(c)
+	} else if cur, _ := threadCheck.Load().(*Context); cur == c {
+		threadCheck.Store((*Context)(nil))
+	}
+}
+
+// checkThread panics if debug mode is enabled and the caller is off the
+// thread, the threads of the loaders are allowed too
+func checkThread() {
+	c, _ := threadCheck.Load().(*Context)
+	if c == nil || c.OnThread() {
+		return
+	}
+	if _, ok := loaderThreads.Load(goid()); ok {
+		return
+	}
+	name := "wrapper"
+	if pc, _, _, ok := runtime.Caller(1); ok {
+		name = runtime.FuncForPC(pc).Name()
+	}
+	panic("glad: " + name + " called off the GL context thread")
+}
+
+// Batch collects functions to run on the context thread with a single
+// submission, e.g. many small uploads prepared by a worker
+type Batch struct {
+	ctx *Context
+	fns []func()
+}
+
+// NewBatch creates an empty batch for the context
+func (c *Context) NewBatch() *Batch {
+	return &Batch{ctx: c}
+}
+
+// Add appends fn to the batch
+func (b *Batch) Add(fn func()) {
+	b.fns = append(b.fns, fn)
+}
+
+// Len returns the number of functions in the batch
+func (b *Batch) Len() int {
+	return len(b.fns)
+}
+
+// Submit sends the functions to the context thread, where they run in
+// order, and empties the batch. A panic does not stop the other functions:
+// the channel receives the first error, or nil when all succeeded. On the
+// context thread the batch runs at the next Process, so don't wait for it
+func (b *Batch) Submit() <-chan error {
+	errc := make(chan error, 1)
+	fns := b.fns
+	b.fns = nil
+	if len(fns) == 0 {
+		errc <- nil
+		close(errc)
+		return errc
+	}
+	b.ctx.submit(fns, errc)
+	return errc
+}
+
+// goid returns the id of the calling goroutine
+func goid() int64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseInt(string(b), 10, 64)
+	return id
+}
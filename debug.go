@@ -0,0 +1,90 @@
+package glad
+
+import "github.com/go-gl/gl/v4.5-core/gl"
+
+// Debug labels and groups are shown by debugging tools (e.g. RenderDoc, apitrace)
+// making it easier to identify objects and sections of a frame in a capture
+
+// objectLabel sets a label on the object identified by name
+// identifier is the namespace of the object, e.g. gl.TEXTURE or gl.BUFFER
+func objectLabel(identifier, name uint32, label string) {
+	gl.ObjectLabel(identifier, name, int32(len(label)), gl.Str(label+"\x00"))
+}
+
+// SetLabel assigns a name to the texture, used by debugging tools
+func (tex Texture) SetLabel(label string) {
+	checkThread()
+	objectLabel(gl.TEXTURE, uint32(tex), label)
+}
+
+// SetLabel assigns a name to the sampler, used by debugging tools
+func (smp Sampler) SetLabel(label string) {
+	checkThread()
+	objectLabel(gl.SAMPLER, uint32(smp), label)
+}
+
+// SetLabel assigns a name to the buffer, used by debugging tools
+func (vbo VertexBufferObject) SetLabel(label string) {
+	checkThread()
+	objectLabel(gl.BUFFER, uint32(vbo), label)
+}
+
+// SetLabel assigns a name to the VAO, used by debugging tools
+func (vao VertexArrayObject) SetLabel(label string) {
+	checkThread()
+	objectLabel(gl.VERTEX_ARRAY, uint32(vao), label)
+}
+
+// SetLabel assigns a name to the FBO, used by debugging tools
+func (fbo FramebufferObject) SetLabel(label string) {
+	checkThread()
+	objectLabel(gl.FRAMEBUFFER, uint32(fbo), label)
+}
+
+// SetLabel assigns a name to the RBO, used by debugging tools
+func (rbo RenderbufferObject) SetLabel(label string) {
+	checkThread()
+	objectLabel(gl.RENDERBUFFER, uint32(rbo), label)
+}
+
+// SetLabel assigns a name to the shader, used by debugging tools
+func (sh Shader) SetLabel(label string) {
+	checkThread()
+	objectLabel(gl.SHADER, uint32(sh), label)
+}
+
+// SetLabel assigns a name to the program, used by debugging tools
+func (pr Program) SetLabel(label string) {
+	checkThread()
+	objectLabel(gl.PROGRAM, uint32(pr), label)
+}
+
+// PushDebugGroup starts a named group of commands, which debugging tools
+// will show as a section. Groups can be nested and must be closed with
+// PopDebugGroup
+func PushDebugGroup(message string) {
+	checkThread()
+	gl.PushDebugGroup(gl.DEBUG_SOURCE_APPLICATION, 0, int32(len(message)), gl.Str(message+"\x00"))
+}
+
+// PopDebugGroup closes the group opened by the last PushDebugGroup
+func PopDebugGroup() {
+	checkThread()
+	gl.PopDebugGroup()
+}
+
+// DebugGroup is a Binder that opens a debug group with its message when bound
+// and closes it when unbound. It can be used with BlockBind, e.g.
+//
+//	defer glad.BlockBind(glad.DebugGroup("shadow pass"), fbo)()
+type DebugGroup string
+
+// Bind pushes the debug group
+func (dg DebugGroup) Bind() {
+	PushDebugGroup(string(dg))
+}
+
+// Unbind pops the debug group
+func (dg DebugGroup) Unbind() {
+	PopDebugGroup()
+}
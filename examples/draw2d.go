@@ -27,11 +27,9 @@ func main() {
 		glad.CoreProfile(true),
 		glad.Resizable(false),
 		glad.ContextVersion(4, 5),
-		//glad.VSync(true),
+		glad.VSync(true),
 	)
 	defer glad.Terminate()
-	// Enable VSync
-	glad.SwapInterval(1)
 
 	var bgCol = []float32{0.3, 0.3, 0.3, 1.0}
 
@@ -21,11 +21,9 @@ func main() {
 		glad.CoreProfile(true),
 		glad.Resizable(false),
 		glad.ContextVersion(4, 5),
-		//glad.VSync(true),
+		glad.VSync(true),
 	)
 	defer glad.Terminate()
-	// Enable VSync
-	glad.SwapInterval(1)
 
 	var (
 		vssTriangle = `#version 440 core
@@ -79,7 +77,7 @@ void main() {
 			glad.NewShader(fssTriangle, gl.FRAGMENT_SHADER),
 		},
 		// Attributes are read in order from each buffer
-		Attributes: []glad.Attr{{0, "pos", 2}, {0, "col", 3}},
+		Attributes: []glad.Attr{{Buff: 0, Name: "pos", Size: 2}, {Buff: 0, Name: "col", Size: 3}},
 		Data: [][]float32{
 			[]float32{ // Interleaved position and color data
 				-1.0, -1.0, 1.0, 0.0, 0.0,
@@ -101,7 +99,7 @@ void main() {
 			glad.NewShader(vssTexture, gl.VERTEX_SHADER),
 			glad.NewShader(fssTexture, gl.FRAGMENT_SHADER),
 		},
-		Attributes: []glad.Attr{{0, "pos", 2}, {1, "uv", 2}}, // First field specifies the Data array to use
+		Attributes: []glad.Attr{{Buff: 0, Name: "pos", Size: 2}, {Buff: 1, Name: "uv", Size: 2}}, // Buff specifies the Data array to use
 		Data: [][]float32{
 			[]float32{-0.9, -0.9, -0.9, 0.9, 0.9, -0.9, 0.9, 0.9}, // pos data
 			[]float32{0.0, 0.0, 0.0, 1.0, 1.0, 0.0, 1.0, 1.0},     // uv data
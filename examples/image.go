@@ -22,11 +22,9 @@ func main() {
 		glad.CoreProfile(true),
 		glad.Resizable(false),
 		glad.ContextVersion(4, 4),
-		//glad.VSync(true),
+		glad.VSync(true),
 	)
 	defer glad.Terminate()
-	// Enable VSync
-	glad.SwapInterval(1)
 
 	var (
 		program    glad.Program
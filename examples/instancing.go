@@ -0,0 +1,104 @@
+package main
+
+// Thousands of sprites drawn with a single call, using instanced rendering
+
+import (
+	"log"
+	"math"
+	"math/rand"
+	"runtime"
+
+	glad "github.com/akiross/go-glad"
+	"github.com/go-gl/gl/v4.5-core/gl"
+)
+
+const SPRITES = 5000
+
+func main() {
+	runtime.LockOSThread()
+
+	log.Println("Starting")
+
+	win := glad.NewOGLWindow(800, 800, "Instancing",
+		glad.CoreProfile(true),
+		glad.Resizable(false),
+		glad.ContextVersion(4, 5),
+		glad.VSync(true),
+	)
+	defer glad.Terminate()
+
+	var (
+		vertexShaderSource = `#version 450 core
+in vec2 pos;
+in vec4 xform; // Per instance: X, Y, scale, rotation
+in vec3 col;   // Per instance: R, G, B
+out vec3 vCol;
+void main() {
+	float c = cos(xform.w), s = sin(xform.w);
+	vec2 p = mat2(c, s, -s, c) * pos * xform.z;
+	gl_Position = vec4(p + xform.xy, 0.0, 1.0);
+	vCol = col;
+}`
+		fragmentShaderSource = `#version 450 core
+in vec3 vCol;
+out vec4 color;
+void main() { color = vec4(vCol, 1.0); }`
+	)
+
+	// Per-vertex data: a small quad
+	// Format: X, Y
+	quad := []float32{
+		-1.0, -1.0,
+		-1.0, 1.0,
+		1.0, -1.0,
+		1.0, 1.0,
+	}
+
+	// Per-instance data, one entry for each sprite
+	// Format: X, Y, scale, rotation
+	xforms := make([]float32, SPRITES*4)
+	// Format: R, G, B
+	colors := make([]float32, SPRITES*3)
+	speeds := make([]float32, SPRITES)
+	for i := 0; i < SPRITES; i++ {
+		xforms[i*4] = rand.Float32()*2 - 1
+		xforms[i*4+1] = rand.Float32()*2 - 1
+		xforms[i*4+2] = 0.005 + rand.Float32()*0.02
+		xforms[i*4+3] = rand.Float32() * math.Pi
+		colors[i*3] = rand.Float32()
+		colors[i*3+1] = rand.Float32()
+		colors[i*3+2] = rand.Float32()
+		speeds[i] = rand.Float32()*0.1 - 0.05
+	}
+
+	sprites := glad.AutoBuild(&glad.Config{
+		Shaders: []glad.Shader{
+			glad.NewShader(vertexShaderSource, gl.VERTEX_SHADER),
+			glad.NewShader(fragmentShaderSource, gl.FRAGMENT_SHADER),
+		},
+		Attributes: []glad.Attr{
+			{Buff: 0, Name: "pos", Size: 2, Label: "quad"},
+			// Transforms and colors advance per instance
+			{Buff: 1, Name: "xform", Size: 4, Divisor: 1, Label: "transforms"},
+			{Buff: 2, Name: "col", Size: 3, Divisor: 1, Label: "colors"},
+		},
+		Data:       [][]float32{quad, xforms, colors},
+		DataUsages: []uint32{gl.STATIC_DRAW, gl.DYNAMIC_DRAW, gl.STATIC_DRAW},
+		Instances:  SPRITES,
+		Primitives: gl.TRIANGLE_STRIP,
+		ClearColor: []float32{0.1, 0.1, 0.1, 1.0},
+	})
+	defer sprites.Delete()
+
+	for !win.ShouldClose() {
+		// Spin the sprites and upload the new transforms
+		for i := 0; i < SPRITES; i++ {
+			xforms[i*4+3] += speeds[i]
+		}
+		sprites.VBOs[1].BufferSubData32(xforms, 0)
+
+		sprites.AutoDraw()
+		win.SwapBuffers()
+		glad.PollEvents()
+	}
+}
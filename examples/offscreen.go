@@ -11,29 +11,23 @@ import (
 	"github.com/go-gl/glfw/v3.2/glfw"
 )
 
-var nextFrame = false
-
-func advanceFrame(w *glfw.Window, key glfw.Key, sc int, act glfw.Action, md glfw.ModifierKey) {
-	if act == glfw.Press && key == glfw.KeySpace {
-		nextFrame = true
-	}
-}
-
 func main() {
 	runtime.LockOSThread()
 
 	log.Println("Starting")
 
-	win := glad.NewOGLWindow(800, 600, "Offscreen",
+	win, err := glad.NewWindow(800, 600, "Offscreen",
 		glad.CoreProfile(true),
 		glad.Resizable(false),
 		glad.ContextVersion(4, 4),
-		//glad.VSync(true),
+		glad.VSync(true),
 	)
+	if err != nil {
+		log.Fatalln(err)
+	}
 	defer glad.Terminate()
-	// Enable VSync
-	glad.SwapInterval(1)
-	win.SetKeyCallback(advanceFrame)
+	input := win.Input()
+	input.Bind("quit", glad.KeyTrigger(glfw.KeyEscape), glad.KeyTrigger(glfw.KeyQ))
 
 	// var (
 	// 	programCol    glad.Program
@@ -186,7 +180,10 @@ void main() { color = vCol; }`
 		// Draw triangles with texture
 		gl.Clear(gl.COLOR_BUFFER_BIT)
 		gl.DrawArrays(gl.TRIANGLE_STRIP, 0, 4)
-		win.SwapBuffers()
-		glad.PollEvents()
+		win.Swap()
+		win.PollEvents()
+		if input.ActionPressed("quit") {
+			win.Close()
+		}
 	}
 }
@@ -23,11 +23,9 @@ func main() {
 		glad.CoreProfile(true),
 		glad.Resizable(false),
 		glad.ContextVersion(4, 4),
-		//glad.VSync(true),
+		glad.VSync(true),
 	)
 	defer glad.Terminate()
-	// Enable VSync
-	glad.SwapInterval(1)
 	bgCol := []float32{0.3, 0.3, 0.3, 1.0}
 
 	var (
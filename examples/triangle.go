@@ -15,15 +15,25 @@ func main() {
 
 	log.Println("Starting")
 
-	win := glad.NewOGLWindow(800, 600, "Hello Triangle",
-		glad.CoreProfile(true),
-		glad.Resizable(false),
-		glad.ContextVersion(4, 5),
-		//glad.VSync(true),
-	)
+	// Try a 4.5 context first, falling back to 4.4 if not available
+	var win *glad.Window
+	for _, minor := range []int{5, 4} {
+		var err error
+		win, err = glad.NewWindow(800, 600, "Hello Triangle",
+			glad.CoreProfile(true),
+			glad.Resizable(false),
+			glad.ContextVersion(4, minor),
+			glad.VSync(true),
+		)
+		if err == nil {
+			break
+		}
+		log.Println("Cannot create window:", err)
+	}
+	if win == nil {
+		log.Fatalln("No suitable OpenGL context")
+	}
 	defer glad.Terminate()
-	// Enable VSync
-	glad.SwapInterval(1)
 
 	var (
 		bgCol              = []float32{0.3, 0.3, 0.3, 1.0}
@@ -79,7 +89,7 @@ func main() {
 	for !win.ShouldClose() {
 		gl.ClearBufferfv(gl.COLOR, 0, &bgCol[0])
 		gl.DrawArrays(gl.TRIANGLES, 0, 3)
-		win.SwapBuffers()
-		glad.PollEvents()
+		win.Swap()
+		win.PollEvents()
 	}
 }
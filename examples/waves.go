@@ -4,12 +4,15 @@ package main
 // https://www.thanassis.space/wavePhysics.html
 
 import (
+	"fmt"
 	"image"
 	"image/color"
 	"log"
 	"runtime"
+	"time"
 
 	glad "github.com/akiross/go-glad"
+	"github.com/akiross/go-glad/mathx"
 	"github.com/go-gl/gl/v4.5-core/gl"
 	"github.com/go-gl/glfw/v3.2/glfw"
 )
@@ -98,41 +101,43 @@ func (g *Grid) Update(damp float32) {
 }
 
 // When clicked on window, set a value on the grid
-func makeClicker(g *Grid) func(*glfw.Window, glfw.MouseButton, glfw.Action, glfw.ModifierKey) {
+// The grid covers the world from -1 to 1 on both axes
+func makeClicker(g *Grid, cam *glad.Camera2D) func(*glfw.Window, glfw.MouseButton, glfw.Action, glfw.ModifierKey) {
 	return func(w *glfw.Window, button glfw.MouseButton, action glfw.Action, mod glfw.ModifierKey) {
-		if action == glfw.Press {
-			cx, cy := w.GetCursorPos()
-			ww, wh := w.GetSize()
-			x, y := float64(cx)/float64(ww), float64(cy)/float64(wh)
-			px, py := int(x*WIDTH), int(HEIGHT-y*HEIGHT)
-			g.Set(px, py, 20.0)
+		if button == glfw.MouseButtonLeft && action == glfw.Press {
+			p := cam.ScreenToWorld(w.GetCursorPos())
+			px, py := int((p[0]+1)/2*WIDTH), int((p[1]+1)/2*HEIGHT)
+			if px >= 0 && px < WIDTH && py >= 0 && py < HEIGHT {
+				g.Set(px, py, 20.0)
+			}
 		}
 	}
 }
 
-func main() {
-	runtime.LockOSThread()
-
-	log.Println("Starting")
-
-	win := glad.NewOGLWindow(SIDE, SIDE, "Waves",
-		glad.CoreProfile(true),
-		glad.Resizable(false),
-		glad.ContextVersion(4, 4),
-		//glad.VSync(true),
-	)
-	defer glad.Terminate()
-	// Enable VSync
-	glad.SwapInterval(1)
+// waves is the application run by glad.Run
+type waves struct {
+	loop    *glad.Loop
+	grid    *Grid
+	cam     *glad.Camera2D
+	program glad.Program
+	locProj int32
+	txr     glad.Texture
+	txrImg  *image.RGBA
+	bgCol   []float32
+	title   time.Time // Wall-clock time of the last title update
+}
 
-	bgCol := []float32{0.3, 0.3, 0.3, 1.0}
+func (a *waves) Init(l *glad.Loop) error {
+	a.loop = l
+	win := l.Window
+	a.bgCol = []float32{0.3, 0.3, 0.3, 1.0}
 
 	vertShader := glad.NewShader(vertexShaderSource, gl.VERTEX_SHADER)
 	fragShader := glad.NewShader(fragmentShaderSource, gl.FRAGMENT_SHADER)
 
-	program := glad.NewProgram()
-	program.AttachShaders(vertShader, fragShader)
-	program.Link()
+	a.program = glad.NewProgram()
+	a.program.AttachShaders(vertShader, fragShader)
+	a.program.Link()
 
 	vertShader.Delete()
 	fragShader.Delete()
@@ -147,35 +152,17 @@ func main() {
 	}
 
 	// Create a texture
-	txrImg := image.NewRGBA(image.Rect(0, 0, WIDTH, HEIGHT))
-
-	updateColors := func(g *Grid, time int) {
-		for y := 0; y < HEIGHT; y++ {
-			for x := 0; x < WIDTH; x++ {
-				switch g.materials[y*WIDTH+x] {
-				case 0: // fluid
-					val := g.Get(x, y)
-					var pcol, ocol, ncol uint8 // Positive color, negative color, overflow color
-					if val > 1 {
-						ocol = 255
-						val -= 1.0
-					}
-					if val > 0 {
-						pcol = uint8(255 * val)
-					} else {
-						ncol = uint8(-255 * val)
-					}
-					txrImg.SetRGBA(x, y, color.RGBA{pcol, ocol, ncol, 255}) //color.RGBA{uint8(float32(time%255) * float32(x%8) / 7.0), col * uint8(float32(y%16)/15.0), col, 255})
-				case 1: // wall
-					txrImg.SetRGBA(x, y, color.RGBA{0, 255, 0, 255})
-				}
-			}
-		}
-	}
+	a.txrImg = image.NewRGBA(image.Rect(0, 0, WIDTH, HEIGHT))
 
-	grid := NewGrid(WIDTH, HEIGHT)
+	a.grid = NewGrid(WIDTH, HEIGHT)
 
-	win.SetMouseButtonCallback(makeClicker(grid))
+	// Pan with the right button, zoom with the wheel
+	a.cam = glad.NewCamera2D(mathx.Vec2{0, 0}, 2)
+	win.SetMouseButtonCallback(makeClicker(a.grid, a.cam))
+	win.SetScrollCallback(func(w *glfw.Window, xoff, yoff float64) {
+		a.cam.Scroll(yoff)
+	})
+	a.locProj = a.program.GetUniformLocation("proj")
 
 	var bindPos uint32 = 0
 	vao := glad.NewVertexArrayObject()
@@ -183,18 +170,18 @@ func main() {
 	vbo.BufferData32(vertPosAndUV, gl.STATIC_DRAW)
 	vao.VertexBuffer32(bindPos, vbo, 0, 4)
 
-	txr := glad.NewTexture(gl.TEXTURE_2D)
-	txr.Storage(1, gl.RGBA8, []int{WIDTH, HEIGHT})
-	txr.Bind(0)
-	txr.Image2D(txrImg)
+	a.txr = glad.NewTexture(gl.TEXTURE_2D)
+	a.txr.Storage(1, gl.RGBA8, []int{WIDTH, HEIGHT})
+	a.txr.Bind(0)
+	a.txr.Image2D(a.txrImg)
 	//txr.Clear(255, 0, 0, 255)
-	txr.SetFilters(gl.NEAREST, gl.NEAREST)
+	a.txr.SetFilters(gl.NEAREST, gl.NEAREST)
 
-	attrPos := program.GetAttributeLocation("pos")
+	attrPos := a.program.GetAttributeLocation("pos")
 	vao.AttribFormat32(attrPos, 2, 0)
 	vao.AttribBinding(bindPos, attrPos)
 
-	attrUV := program.GetAttributeLocation("uv")
+	attrUV := a.program.GetAttributeLocation("uv")
 	vao.AttribFormat32(attrUV, 2, 2)
 	vao.AttribBinding(bindPos, attrUV)
 
@@ -202,21 +189,83 @@ func main() {
 	vao.EnableAttrib(attrUV)
 
 	vao.Bind()
+	return nil
+}
+
+// Update advances the waves at a fixed rate, independent of the frame rate
+func (a *waves) Update(dt float64) {
+	a.grid.Update(DAMP)
+}
 
-	i := 0
-	for !win.ShouldClose() {
-		gl.ClearBufferfv(gl.COLOR, 0, &bgCol[0])
-		gl.Clear(gl.COLOR_BUFFER_BIT)
-		program.Use()
-		gl.DrawArrays(gl.TRIANGLE_STRIP, 0, 4)
+func (a *waves) Render(alpha float64) {
+	a.updateColors()
+	a.txr.Image2D(a.txrImg)
+
+	gl.ClearBufferfv(gl.COLOR, 0, &a.bgCol[0])
+	gl.Clear(gl.COLOR_BUFFER_BIT)
+	a.cam.Update(a.loop.Window.Window, 0)
+	a.program.SetUniformMat4(a.locProj, a.cam.Projection())
+	a.program.Use()
+	gl.DrawArrays(gl.TRIANGLE_STRIP, 0, 4)
+
+	// Show the frame rate once per second of real time: the simulated time
+	// of the loop only advances with Update and can drift from it
+	if time.Since(a.title) >= time.Second {
+		a.title = time.Now()
+		st := a.loop.Stats()
+		a.loop.Window.SetTitle(fmt.Sprintf("Waves - %.0f FPS, %.2f ms", st.FPS, st.FrameTime.Seconds()*1000))
+	}
+}
 
-		i++
-		grid.Update(DAMP)
-		updateColors(grid, i)
-		txr.Image2D(txrImg)
+func (a *waves) Resize(width, height int) {
+	gl.Viewport(0, 0, int32(width), int32(height))
+}
+
+func (a *waves) Close() {}
+
+func (a *waves) updateColors() {
+	g := a.grid
+	for y := 0; y < HEIGHT; y++ {
+		for x := 0; x < WIDTH; x++ {
+			switch g.materials[y*WIDTH+x] {
+			case 0: // fluid
+				val := g.Get(x, y)
+				var pcol, ocol, ncol uint8 // Positive color, negative color, overflow color
+				if val > 1 {
+					ocol = 255
+					val -= 1.0
+				}
+				if val > 0 {
+					pcol = uint8(255 * val)
+				} else {
+					ncol = uint8(-255 * val)
+				}
+				a.txrImg.SetRGBA(x, y, color.RGBA{pcol, ocol, ncol, 255})
+			case 1: // wall
+				a.txrImg.SetRGBA(x, y, color.RGBA{0, 255, 0, 255})
+			}
+		}
+	}
+}
+
+func main() {
+	runtime.LockOSThread()
+
+	log.Println("Starting")
+
+	win, err := glad.NewWindow(SIDE, SIDE, "Waves",
+		glad.CoreProfile(true),
+		glad.Resizable(false),
+		glad.ContextVersion(4, 4),
+		glad.VSync(true),
+	)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer glad.Terminate()
 
-		win.SwapBuffers()
-		glad.PollEvents()
+	if err := glad.Run(win, &waves{}, glad.FixedStep(time.Second/60)); err != nil {
+		log.Fatalln(err)
 	}
 }
 
@@ -225,7 +274,8 @@ var (
 in vec2 pos;
 in vec2 uv;
 out vec2 vUV;
-void main() { gl_Position = vec4(pos, 0.0, 1.0); vUV = uv; }`
+uniform mat4 proj;
+void main() { gl_Position = proj * vec4(pos, 0.0, 1.0); vUV = uv; }`
 	fragmentShaderSource = `#version 440 core
 in vec2 vUV;
 out vec4 color;
@@ -13,28 +13,40 @@ type FramebufferObject uint32
 
 // NewFramebuffer creates a new FBO
 func NewFramebuffer() FramebufferObject {
+	checkThread()
 	var fbo uint32
 	gl.CreateFramebuffers(1, &fbo)
+	registry.track("FramebufferObject", fbo)
 	return FramebufferObject(fbo)
 }
 
 // Delete the FBO, freeing its name
 func (fbo FramebufferObject) Delete() {
+	checkThread()
 	f := uint32(fbo)
 	gl.DeleteFramebuffers(1, &f)
+	stateCache().forget(bindFramebuffer, f)
+	registry.untrack("FramebufferObject", f)
 }
 
 // Bind the FBO to the framebuffer target, allowing to use it for GL output
 func (fbo FramebufferObject) Bind() {
-	gl.BindFramebuffer(gl.FRAMEBUFFER, uint32(fbo))
+	checkThread()
+	if stateCache().bindFramebuffer(uint32(fbo)) {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, uint32(fbo))
+	}
 }
 
 // Unbind the FBO, restoring the default window-system framebuffer
 func (fbo FramebufferObject) Unbind() {
-	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	checkThread()
+	if stateCache().bindFramebuffer(0) {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	}
 }
 
 // Texture attaches a texture level to the FBO
 func (fbo FramebufferObject) Texture(att uint32, texture Texture) {
+	checkThread()
 	gl.NamedFramebufferTexture(uint32(fbo), att, uint32(texture), 0)
 }
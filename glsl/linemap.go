@@ -0,0 +1,57 @@
+package glsl
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Origin returns the file and the line (1-based) where a line of the output
+// comes from. Lines added by the preprocessor (#version and defines) have
+// line 0 and file "<options>"
+func (s *Source) Origin(line int) (file string, fileLine int) {
+	if line < 1 || line > len(s.lines) {
+		return "", 0
+	}
+	o := s.lines[line-1]
+	if o.line == 0 {
+		return "<options>", 0
+	}
+	return o.file, o.line
+}
+
+// logLocation matches the position of a message in the info logs of the
+// common drivers, as string:line or string(line):
+//
+//	0:12(5): error: ...            Mesa
+//	ERROR: 0:12: 'x' : undeclared  AMD, Intel on Windows, ANGLE
+//	0(12) : error C1008: ...       NVIDIA
+var logLocation = regexp.MustCompile(`^(\s*(?:ERROR|WARNING|error|warning)?:?\s*)(\d+)(?::(\d+)|\((\d+)\))`)
+
+// TranslateLog rewrites the positions in an info log of the driver, which
+// refer to the lines of Text, to the original file and line
+func (s *Source) TranslateLog(log string) string {
+	lines := strings.Split(log, "\n")
+	for i, l := range lines {
+		m := logLocation.FindStringSubmatchIndex(l)
+		if m == nil {
+			continue
+		}
+		numStart, numEnd := m[6], m[7] // string:line
+		if numStart < 0 {
+			numStart, numEnd = m[8], m[9] // string(line)
+		}
+		n, err := strconv.Atoi(l[numStart:numEnd])
+		if err != nil {
+			continue
+		}
+		file, fl := s.Origin(n)
+		if file == "" {
+			continue
+		}
+		end := m[1]
+		lines[i] = l[:m[4]] + fmt.Sprintf("%s:%d", file, fl) + l[end:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,37 @@
+package glsl
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestTranslateLog(t *testing.T) {
+	fsys := fstest.MapFS{
+		"util.glsl": {Data: []byte("float f() {\n\treturn x;\n}\n")},
+	}
+	src, err := PreprocessString(fsys, "main.frag", "#version 330\n#include \"util.glsl\"\nvoid main() { y; }\n",
+		&Options{Defines: map[string]string{"A": ""}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Output lines: 1 #version, 2 #define A, 3-5 util.glsl, 6 main
+	tests := []struct {
+		name, log, want string
+	}{
+		{"Mesa", "0:4(9): error: `x' undeclared", "util.glsl:2(9): error: `x' undeclared"},
+		{"AMD", "ERROR: 0:6: 'y' : undeclared identifier", "ERROR: main.frag:3: 'y' : undeclared identifier"},
+		{"Warning", "WARNING: 0:2: extension not supported", "WARNING: <options>:0: extension not supported"},
+		{"NVIDIA", "0(4) : error C1008: undefined variable \"x\"", "util.glsl:2 : error C1008: undefined variable \"x\""},
+		{"OutOfRange", "0:99(1): error: unexpected end", "0:99(1): error: unexpected end"},
+		{"Other", "Linking failed", "Linking failed"},
+		{"MultiLine", "0:4(9): error: a\n0(6) : error C0000: b\n",
+			"util.glsl:2(9): error: a\nmain.frag:3 : error C0000: b\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := src.TranslateLog(tt.log); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+// Package glsl prepares GLSL sources before they are passed to the driver.
+// The preprocessor resolves #include directives, which GLSL does not
+// support, injects the #version line and #define directives chosen for each
+// compile, and keeps track of where each line of the output comes from, so
+// that the errors reported by the driver can be translated back to the
+// original files. Other directives (#ifdef, #if, ...) are left to the
+// compiler of the driver
+package glsl
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Options are the settings of a single compile
+type Options struct {
+	// Version replaces the #version of the main file, e.g. "450 core"
+	// If empty, the #version of the file is kept
+	Version string
+	// Defines are added after the #version line, sorted by name, e.g.
+	// {"SHADOWS": "", "LIGHTS": "4"}
+	Defines map[string]string
+}
+
+// Source is the result of the preprocessor
+type Source struct {
+	Text  string   // Source to pass to the driver
+	Files []string // Files read, starting with the main one
+	lines []origin // Origin of each line of Text
+}
+
+// origin is the position of a line in the original files
+type origin struct {
+	file string
+	line int // 1-based, 0 for the lines added by the preprocessor
+}
+
+// Preprocess reads file from fsys and resolves its includes
+// Included paths are relative to the including file, or to the root of fsys
+// with #include <file>. Each file is included only once, so include guards
+// are not needed (#pragma once is accepted and removed). Since conditionals
+// are left to the driver, a file first included inside #if/#ifdef/#ifndef
+// can't be included again, e.g. in the #else branch: that is an error and
+// the file should be included once before the conditional. On errors the
+// Source is still returned with only Files set, the files read so far, e.g.
+// to watch them for changes that could fix the error
+func Preprocess(fsys fs.FS, file string, opts *Options) (*Source, error) {
+	src, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return &Source{Files: []string{file}}, err
+	}
+	return PreprocessString(fsys, file, string(src), opts)
+}
+
+// PreprocessString is like Preprocess for a source not read from fsys
+// name is used in errors and to resolve the relative includes, fsys can be
+// nil if the source has no includes
+func PreprocessString(fsys fs.FS, name, src string, opts *Options) (*Source, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	p := &preprocessor{
+		fsys:     fsys,
+		opts:     opts,
+		included: map[string]bool{name: false},
+		out:      &Source{Files: []string{name}},
+	}
+	if err := p.file(name, src, true); err != nil {
+		return &Source{Files: p.out.Files}, err
+	}
+	if !p.header {
+		// No #version in the source: defines go on top
+		p.writeHeader(true)
+	}
+	p.out.Text = p.sb.String()
+	return p.out, nil
+}
+
+type preprocessor struct {
+	fsys     fs.FS
+	opts     *Options
+	included map[string]bool // Files included, true if inside a conditional
+	stack    []string        // Files being included, to report cycles
+	cond     int             // Depth of the #if blocks open
+	sb       strings.Builder
+	out      *Source
+	header   bool // #version and defines already written
+}
+
+// emit appends a line to the output
+func (p *preprocessor) emit(text, file string, line int) {
+	p.sb.WriteString(text)
+	p.sb.WriteByte('\n')
+	p.out.lines = append(p.out.lines, origin{file, line})
+}
+
+// writeHeader writes the #version (if any) and the defines, at the end of
+// the output or on top of it
+func (p *preprocessor) writeHeader(top bool) {
+	var hdr []string
+	if p.opts.Version != "" {
+		hdr = append(hdr, "#version "+p.opts.Version)
+	}
+	names := make([]string, 0, len(p.opts.Defines))
+	for n := range p.opts.Defines {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		hdr = append(hdr, strings.TrimSpace("#define "+n+" "+p.opts.Defines[n]))
+	}
+	p.header = true
+	if len(hdr) == 0 {
+		return
+	}
+	text := strings.Join(hdr, "\n") + "\n"
+	lines := make([]origin, len(hdr))
+	if top {
+		rest := p.sb.String()
+		p.sb.Reset()
+		p.sb.WriteString(text + rest)
+		p.out.lines = append(lines, p.out.lines...)
+		return
+	}
+	p.sb.WriteString(text)
+	p.out.lines = append(p.out.lines, lines...)
+}
+
+// file processes the lines of a file
+func (p *preprocessor) file(name, src string, main bool) error {
+	p.stack = append(p.stack, name)
+	defer func() { p.stack = p.stack[:len(p.stack)-1] }()
+
+	inComment := false
+	for i, line := range strings.Split(strings.TrimSuffix(src, "\n"), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		n := i + 1
+		wasComment := inComment
+		inComment = blockComment(line, inComment)
+		dir, arg := directive(line)
+		if wasComment || dir == "" {
+			p.emit(line, name, n)
+			continue
+		}
+		switch dir {
+		case "version":
+			if !main {
+				return fmt.Errorf("%s:%d: #version in an included file", name, n)
+			}
+			if p.header {
+				return fmt.Errorf("%s:%d: duplicate #version", name, n)
+			}
+			if p.opts.Version == "" {
+				p.emit(line, name, n)
+			}
+			p.writeHeader(false)
+		case "pragma":
+			if arg != "once" {
+				p.emit(line, name, n)
+			}
+		case "include":
+			if err := p.include(name, n, arg); err != nil {
+				return err
+			}
+		case "if", "ifdef", "ifndef":
+			p.cond++
+			p.emit(line, name, n)
+		case "endif":
+			if p.cond > 0 {
+				p.cond--
+			}
+			p.emit(line, name, n)
+		default:
+			p.emit(line, name, n)
+		}
+	}
+	return nil
+}
+
+// include inserts the file named by the argument of an #include directive
+func (p *preprocessor) include(from string, line int, arg string) error {
+	if len(arg) < 2 || !(arg[0] == '"' && arg[len(arg)-1] == '"' || arg[0] == '<' && arg[len(arg)-1] == '>') {
+		return fmt.Errorf("%s:%d: malformed #include %s", from, line, arg)
+	}
+	target := arg[1 : len(arg)-1]
+	if arg[0] == '"' {
+		target = path.Join(path.Dir(from), target)
+	} else {
+		target = path.Clean(target)
+	}
+	for _, f := range p.stack {
+		if f == target {
+			return fmt.Errorf("%s:%d: include cycle: %s -> %s", from, line, strings.Join(p.stack, " -> "), target)
+		}
+	}
+	if cond, ok := p.included[target]; ok {
+		if cond {
+			return fmt.Errorf("%s:%d: %s included again, after being included inside a conditional block", from, line, target)
+		}
+		return nil
+	}
+	if p.fsys == nil {
+		return fmt.Errorf("%s:%d: no files to include %s from", from, line, target)
+	}
+	src, err := fs.ReadFile(p.fsys, target)
+	if err != nil {
+		return fmt.Errorf("%s:%d: %v", from, line, err)
+	}
+	p.included[target] = p.cond > 0
+	p.out.Files = append(p.out.Files, target)
+	return p.file(target, string(src), false)
+}
+
+// directive returns the name and the argument of a preprocessor directive,
+// or an empty name if the line is not a directive
+func directive(line string) (name, arg string) {
+	s := strings.TrimSpace(line)
+	if !strings.HasPrefix(s, "#") {
+		return "", ""
+	}
+	s = strings.TrimSpace(s[1:])
+	if i := strings.Index(s, "//"); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	if i := strings.IndexAny(s, " \t"); i >= 0 {
+		return s[:i], strings.TrimSpace(s[i+1:])
+	}
+	return s, ""
+}
+
+// blockComment returns if a /* */ comment is open at the end of the line
+func blockComment(line string, open bool) bool {
+	for i := 0; i < len(line)-1; i++ {
+		switch {
+		case open && line[i] == '*' && line[i+1] == '/':
+			open = false
+			i++
+		case !open && line[i] == '/' && line[i+1] == '*':
+			open = true
+			i++
+		case !open && line[i] == '/' && line[i+1] == '/':
+			return false
+		}
+	}
+	return open
+}
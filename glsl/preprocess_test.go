@@ -0,0 +1,157 @@
+package glsl
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestHeader(t *testing.T) {
+	defines := map[string]string{"SHADOWS": "", "LIGHTS": "4"}
+	tests := []struct {
+		name string
+		src  string
+		opts *Options
+		want string
+	}{
+		{"NoOptions", "#version 330\nvoid main() {}\n", nil, "#version 330\nvoid main() {}\n"},
+		{"Version", "#version 330\nvoid main() {}\n", &Options{Version: "450 core"}, "#version 450 core\nvoid main() {}\n"},
+		{"Defines", "#version 330\nvoid main() {}\n", &Options{Defines: defines},
+			"#version 330\n#define LIGHTS 4\n#define SHADOWS\nvoid main() {}\n"},
+		{"Both", "// Comment\n#version 330 core\nvoid main() {}\n", &Options{Version: "450", Defines: defines},
+			"// Comment\n#version 450\n#define LIGHTS 4\n#define SHADOWS\nvoid main() {}\n"},
+		{"NoVersion", "void main() {}\n", &Options{Version: "450", Defines: defines},
+			"#version 450\n#define LIGHTS 4\n#define SHADOWS\nvoid main() {}\n"},
+		{"CRLF", "#version 330\r\nvoid main() {}\r\n", nil, "#version 330\nvoid main() {}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := PreprocessString(nil, "main.vert", tt.src, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if src.Text != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", src.Text, tt.want)
+			}
+			if n := strings.Count(src.Text, "\n"); len(src.lines) != n {
+				t.Errorf("%d origins for %d lines", len(src.lines), n)
+			}
+		})
+	}
+}
+
+var files = fstest.MapFS{
+	"main.frag": {Data: []byte(`#version 330
+#include "lib/light.glsl"
+#include <common.glsl> // Already included
+void main() {}
+`)},
+	"lib/light.glsl": {Data: []byte(`#pragma once
+#include "brdf.glsl"
+#include <common.glsl>
+vec3 light() { return brdf(); }
+`)},
+	"lib/brdf.glsl": {Data: []byte(`/* Included by light.glsl
+#include "missing.glsl"
+*/
+vec3 brdf() { return vec3(PI); }
+`)},
+	"common.glsl": {Data: []byte(`#define PI 3.14159
+`)},
+	"cycle/a.glsl":    {Data: []byte("#include \"b.glsl\"\n")},
+	"cycle/b.glsl":    {Data: []byte("#include \"a.glsl\"\n")},
+	"cycle.frag":      {Data: []byte("#version 330\n#include \"cycle/a.glsl\"\n")},
+	"missing.frag":    {Data: []byte("#include \"lib/missing.glsl\"\n")},
+	"nested.frag":     {Data: []byte("#include \"bad/nested.glsl\"\n")},
+	"bad/nested.glsl": {Data: []byte("#pragma once\n#include <nothing.glsl>\n")},
+	"malformed.frag":  {Data: []byte("#include common.glsl\n")},
+	"version.frag":    {Data: []byte("#include \"version.glsl\"\n")},
+	"version.glsl":    {Data: []byte("#version 330\n")},
+	"twice.frag":      {Data: []byte("#version 330\n#version 450\n")},
+	"branches.frag":   {Data: []byte("#ifdef A\n#include <common.glsl>\n#else\n#include <common.glsl>\n#endif\n")},
+	"before.frag":     {Data: []byte("#include <common.glsl>\n#ifdef A\n#include <common.glsl>\n#endif\n")},
+}
+
+func TestIncludes(t *testing.T) {
+	src, err := Preprocess(files, "main.frag", &Options{Defines: map[string]string{"X": "1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `#version 330
+#define X 1
+/* Included by light.glsl
+#include "missing.glsl"
+*/
+vec3 brdf() { return vec3(PI); }
+#define PI 3.14159
+vec3 light() { return brdf(); }
+void main() {}
+`
+	if src.Text != want {
+		t.Errorf("got\n%s\nwant\n%s", src.Text, want)
+	}
+	if got := fmt.Sprint(src.Files); got != "[main.frag lib/light.glsl lib/brdf.glsl common.glsl]" {
+		t.Errorf("got files %s", got)
+	}
+
+	origins := []struct {
+		file string
+		line int
+	}{
+		{"main.frag", 1}, {"<options>", 0}, {"lib/brdf.glsl", 1}, {"lib/brdf.glsl", 2}, {"lib/brdf.glsl", 3},
+		{"lib/brdf.glsl", 4}, {"common.glsl", 1}, {"lib/light.glsl", 4}, {"main.frag", 4},
+	}
+	for i, o := range origins {
+		if file, line := src.Origin(i + 1); file != o.file || line != o.line {
+			t.Errorf("line %d: got origin %s:%d, want %s:%d", i+1, file, line, o.file, o.line)
+		}
+	}
+	if file, line := src.Origin(len(origins) + 1); file != "" || line != 0 {
+		t.Errorf("line past the end: got origin %s:%d", file, line)
+	}
+
+	// Included unconditionally first, so it can be skipped in the conditional
+	src, err = Preprocess(files, "before.frag", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "#define PI 3.14159\n#ifdef A\n#endif\n"; src.Text != want {
+		t.Errorf("got\n%s\nwant\n%s", src.Text, want)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		file  string
+		err   string
+		files string // Files read before the error
+	}{
+		{"nothing.frag", "file does not exist", "[nothing.frag]"},
+		{"cycle.frag", "cycle/b.glsl:1: include cycle: cycle.frag -> cycle/a.glsl -> cycle/b.glsl -> cycle/a.glsl",
+			"[cycle.frag cycle/a.glsl cycle/b.glsl]"},
+		{"missing.frag", "missing.frag:1: open lib/missing.glsl: file does not exist", "[missing.frag]"},
+		{"nested.frag", "bad/nested.glsl:2: open nothing.glsl", "[nested.frag bad/nested.glsl]"},
+		{"malformed.frag", "malformed.frag:1: malformed #include common.glsl", "[malformed.frag]"},
+		{"version.frag", "version.glsl:1: #version in an included file", "[version.frag version.glsl]"},
+		{"twice.frag", "twice.frag:2: duplicate #version", "[twice.frag]"},
+		{"branches.frag", "branches.frag:4: common.glsl included again, after being included inside a conditional block",
+			"[branches.frag common.glsl]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			src, err := Preprocess(files, tt.file, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("got error %v, want %q", err, tt.err)
+			}
+			if src == nil || fmt.Sprint(src.Files) != tt.files || src.Text != "" {
+				t.Errorf("got source %+v, want only files %s", src, tt.files)
+			}
+		})
+	}
+
+	_, err := PreprocessString(nil, "main.vert", "#include \"a.glsl\"\n", nil)
+	if err == nil || !strings.Contains(err.Error(), "main.vert:1: no files to include a.glsl from") {
+		t.Errorf("got error %v without files to include", err)
+	}
+}
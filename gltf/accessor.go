@@ -0,0 +1,239 @@
+package gltf
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Accessor is a typed array of elements, e.g. the positions of a primitive
+// Data holds the elements tightly packed, in little endian (the byte order
+// expected by GL on all common platforms), with sparse values already applied
+type Accessor struct {
+	Name          string
+	ComponentType uint32 // Type of each component, e.g. Float, matches the GL enums
+	Normalized    bool   // Integer components are mapped to [0, 1] or [-1, 1]
+	Count         int    // Number of elements
+	Type          string // SCALAR, VEC2, VEC3, VEC4, MAT2, MAT3 or MAT4
+	Components    int    // Number of components of each element
+	Min, Max      []float64
+	Data          []byte
+}
+
+// typeComponents is the number of components of each accessor type
+var typeComponents = map[string]int{
+	"SCALAR": 1, "VEC2": 2, "VEC3": 3, "VEC4": 4, "MAT2": 4, "MAT3": 9, "MAT4": 16,
+}
+
+// maxAccessorSize limits the data of accessors without a buffer view, which
+// are initialized with zeros
+const maxAccessorSize = 1 << 30
+
+// componentSize returns the size in bytes of a component type, 0 if invalid
+func componentSize(typ uint32) int {
+	switch typ {
+	case Byte, UnsignedByte:
+		return 1
+	case Short, UnsignedShort:
+		return 2
+	case UnsignedInt, Float:
+		return 4
+	}
+	return 0
+}
+
+// ElementSize returns the size in bytes of an element in Data
+func (a *Accessor) ElementSize() int {
+	return a.Components * componentSize(a.ComponentType)
+}
+
+// component returns the i-th component in Data as float64
+// Normalized integers are converted to floats as described by the spec
+func (a *Accessor) component(i int) float64 {
+	d := a.Data
+	switch a.ComponentType {
+	case Byte:
+		v := float64(int8(d[i]))
+		if a.Normalized {
+			return math.Max(v/127, -1)
+		}
+		return v
+	case UnsignedByte:
+		if a.Normalized {
+			return float64(d[i]) / 255
+		}
+		return float64(d[i])
+	case Short:
+		v := float64(int16(binary.LittleEndian.Uint16(d[i*2:])))
+		if a.Normalized {
+			return math.Max(v/32767, -1)
+		}
+		return v
+	case UnsignedShort:
+		v := float64(binary.LittleEndian.Uint16(d[i*2:]))
+		if a.Normalized {
+			return v / 65535
+		}
+		return v
+	case UnsignedInt:
+		return float64(binary.LittleEndian.Uint32(d[i*4:]))
+	default:
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(d[i*4:])))
+	}
+}
+
+// Floats returns all the components converted to float32, e.g. to upload
+// them with BufferData32 or to process them on the CPU
+func (a *Accessor) Floats() []float32 {
+	out := make([]float32, a.Count*a.Components)
+	for i := range out {
+		out[i] = float32(a.component(i))
+	}
+	return out
+}
+
+// Uint32s returns all the components as uint32, usually to read indices
+// It panics if the components are not unsigned integers
+func (a *Accessor) Uint32s() []uint32 {
+	out := make([]uint32, a.Count*a.Components)
+	for i := range out {
+		switch a.ComponentType {
+		case UnsignedByte:
+			out[i] = uint32(a.Data[i])
+		case UnsignedShort:
+			out[i] = uint32(binary.LittleEndian.Uint16(a.Data[i*2:]))
+		case UnsignedInt:
+			out[i] = binary.LittleEndian.Uint32(a.Data[i*4:])
+		default:
+			panic("Accessor Uint32s requires unsigned integer components")
+		}
+	}
+	return out
+}
+
+func (imp *importer) loadAccessors() error {
+	for i := range imp.doc.Accessors {
+		a, err := imp.accessor(&imp.doc.Accessors[i])
+		if err != nil {
+			return fmt.Errorf("accessor %d: %v", i, err)
+		}
+		imp.out.Accessors = append(imp.out.Accessors, a)
+	}
+	return nil
+}
+
+// accessor decodes the data of an accessor
+func (imp *importer) accessor(ja *jsonAccessor) (*Accessor, error) {
+	a := Accessor{
+		Name:          ja.Name,
+		ComponentType: ja.ComponentType,
+		Normalized:    ja.Normalized,
+		Count:         ja.Count,
+		Type:          ja.Type,
+		Components:    typeComponents[ja.Type],
+		Min:           ja.Min,
+		Max:           ja.Max,
+	}
+	compSize := componentSize(a.ComponentType)
+	if compSize == 0 {
+		return nil, fmt.Errorf("invalid component type %d", a.ComponentType)
+	}
+	if a.Components == 0 {
+		return nil, fmt.Errorf("invalid type %q", a.Type)
+	}
+	if a.Count < 0 {
+		return nil, fmt.Errorf("invalid count %d", a.Count)
+	}
+	if ja.ByteOffset < 0 {
+		return nil, fmt.Errorf("invalid byte offset %d", ja.ByteOffset)
+	}
+	elemSize := a.ElementSize()
+	if a.Count > maxAccessorSize/elemSize {
+		return nil, fmt.Errorf("count %d too large", a.Count)
+	}
+
+	// Columns of matrices are aligned to 4 bytes in the buffer
+	cols, colSize := 1, elemSize
+	switch a.Type {
+	case "MAT2", "MAT3", "MAT4":
+		cols = int(math.Sqrt(float64(a.Components)))
+		colSize = cols * compSize
+	}
+	colStride := (colSize + 3) &^ 3
+	srcSize := cols * colStride
+	if cols == 1 {
+		srcSize = elemSize
+	}
+
+	// The range is validated before allocating the data, so that a malformed
+	// file can't request more memory than its size
+	var data []byte
+	stride := srcSize
+	if ja.BufferView != nil {
+		var err error
+		if data, err = imp.view(*ja.BufferView); err != nil {
+			return nil, err
+		}
+		if s := imp.doc.BufferViews[*ja.BufferView].ByteStride; s != 0 {
+			if s < srcSize {
+				return nil, fmt.Errorf("byte stride %d smaller than the element size %d", s, srcSize)
+			}
+			stride = s
+		}
+		// Space after the first element, compared without overflows
+		if avail := len(data) - ja.ByteOffset - srcSize; a.Count > 0 && (avail < 0 || a.Count-1 > avail/stride) {
+			return nil, fmt.Errorf("data out of range of buffer view %d", *ja.BufferView)
+		}
+	}
+	a.Data = make([]byte, a.Count*elemSize) // Zeros without a buffer view
+	for e := 0; e < a.Count && data != nil; e++ {
+		src := data[ja.ByteOffset+e*stride:]
+		for c := 0; c < cols; c++ {
+			copy(a.Data[e*elemSize+c*colSize:], src[c*colStride:c*colStride+colSize])
+		}
+	}
+	if ja.Sparse != nil {
+		if err := imp.applySparse(&a, ja); err != nil {
+			return nil, fmt.Errorf("sparse: %v", err)
+		}
+	}
+	return &a, nil
+}
+
+// applySparse replaces the elements listed in the sparse part of an accessor
+func (imp *importer) applySparse(a *Accessor, ja *jsonAccessor) error {
+	sp := ja.Sparse
+	idxData, err := imp.view(sp.Indices.BufferView)
+	if err != nil {
+		return err
+	}
+	valData, err := imp.view(sp.Values.BufferView)
+	if err != nil {
+		return err
+	}
+	idx := Accessor{ComponentType: sp.Indices.ComponentType, Count: sp.Count, Components: 1}
+	idxSize := componentSize(idx.ComponentType)
+	elemSize := a.ElementSize()
+	if idxSize == 0 || idx.ComponentType == Byte || idx.ComponentType == Short || idx.ComponentType == Float {
+		return fmt.Errorf("invalid indices component type %d", idx.ComponentType)
+	}
+	if sp.Count < 1 || sp.Count > a.Count {
+		return fmt.Errorf("invalid count %d", sp.Count)
+	}
+	if sp.Indices.ByteOffset < 0 || sp.Values.ByteOffset < 0 {
+		return fmt.Errorf("invalid byte offset")
+	}
+	// Count is limited by the one of the accessor, so sizes do not overflow
+	if sp.Indices.ByteOffset > len(idxData)-sp.Count*idxSize || sp.Values.ByteOffset > len(valData)-sp.Count*elemSize {
+		return fmt.Errorf("data out of range")
+	}
+	idx.Data = idxData[sp.Indices.ByteOffset:]
+	values := valData[sp.Values.ByteOffset:]
+	for i, e := range idx.Uint32s() {
+		if int(e) >= a.Count {
+			return fmt.Errorf("index %d out of range", e)
+		}
+		copy(a.Data[int(e)*elemSize:(int(e)+1)*elemSize], values[i*elemSize:])
+	}
+	return nil
+}
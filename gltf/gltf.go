@@ -0,0 +1,502 @@
+// Package gltf imports glTF 2.0 assets, both .gltf (with external or embedded
+// resources) and binary .glb files. The asset is decoded in Go structures
+// that can be uploaded with the wrappers of glad: accessors provide tightly
+// packed data and GL enums for the buffers and vertex formats, textures
+// provide decoded images and sampler parameters
+// Import is pure Go and does not require an OpenGL context
+package gltf
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg" // Image formats allowed by glTF
+	_ "image/png"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Component types of accessors, they match the GL enums
+const (
+	Byte          uint32 = 5120
+	UnsignedByte  uint32 = 5121
+	Short         uint32 = 5122
+	UnsignedShort uint32 = 5123
+	UnsignedInt   uint32 = 5125
+	Float         uint32 = 5126
+)
+
+// Sampler parameters, they match the GL enums
+const (
+	Nearest            int32 = 9728
+	Linear             int32 = 9729
+	LinearMipmapLinear int32 = 9987
+	Repeat             int32 = 10497
+	ClampToEdge        int32 = 33071
+	MirroredRepeat     int32 = 33648
+)
+
+// primitiveTriangles is the default mode of primitives
+const primitiveTriangles uint32 = 4
+
+// Document is an imported glTF asset
+// All the objects in the asset are listed, in the order of the file
+type Document struct {
+	Scenes    []*Scene
+	Scene     *Scene // Scene to display, nil if the asset has no scenes
+	Nodes     []*Node
+	Meshes    []*Mesh
+	Materials []*Material
+	Textures  []*Texture
+	Samplers  []*Sampler
+	Cameras   []*Camera
+	Accessors []*Accessor
+}
+
+// Scene is a set of root nodes
+type Scene struct {
+	Name  string
+	Nodes []*Node
+}
+
+// Mesh is a set of primitives, each one drawn with its own material
+type Mesh struct {
+	Name       string
+	Primitives []*Primitive
+}
+
+// Primitive is a geometry to draw with a single draw call
+// Attributes maps the glTF attribute names (e.g. POSITION, NORMAL, TEXCOORD_0)
+// to their data. Each accessor can be uploaded to its own buffer, e.g.
+//
+//	vbo.BufferDataStructs(acc.Data, gl.STATIC_DRAW)
+//	vao.VertexBuffer(i, vbo, 0, int32(acc.ElementSize()))
+//	vao.AttribFormat(loc, int32(acc.Components), acc.ComponentType, acc.Normalized, 0)
+type Primitive struct {
+	Mode       uint32 // Primitives to draw, matches the GL enums (e.g. gl.TRIANGLES)
+	Attributes map[string]*Accessor
+	Indices    *Accessor // nil if not indexed
+	Material   *Material // nil to use a default material
+}
+
+// TextureRef is a reference to a texture used by a material
+type TextureRef struct {
+	Texture  *Texture
+	TexCoord int // Index of the TEXCOORD_n attribute to use
+}
+
+// Material is a PBR metallic-roughness material
+// Factors multiply the values read from the corresponding textures, if any
+type Material struct {
+	Name                     string
+	BaseColorFactor          [4]float32
+	BaseColorTexture         *TextureRef
+	MetallicFactor           float32
+	RoughnessFactor          float32
+	MetallicRoughnessTexture *TextureRef // Metalness in B, roughness in G
+	NormalTexture            *TextureRef
+	NormalScale              float32
+	OcclusionTexture         *TextureRef // Occlusion in R
+	OcclusionStrength        float32
+	EmissiveTexture          *TextureRef
+	EmissiveFactor           [3]float32
+	AlphaMode                string // OPAQUE, MASK or BLEND
+	AlphaCutoff              float32
+	DoubleSided              bool
+}
+
+// Texture is an image with the parameters to sample it
+// The image can be uploaded with glad.NewTextureFromImage and the sampler
+// parameters set with SetFilters and SetWrap of glad.Sampler or glad.Texture
+type Texture struct {
+	Name    string
+	Image   image.Image
+	Sampler *Sampler
+}
+
+// Sampler holds filters and wrap modes of a texture, they match the GL enums
+type Sampler struct {
+	MagFilter, MinFilter int32
+	WrapS, WrapT         int32
+}
+
+// defaultSampler is used by textures without a sampler
+var defaultSampler = Sampler{Linear, LinearMipmapLinear, Repeat, Repeat}
+
+// Load reads a .gltf or .glb file, external resources are looked for in the
+// same directory of the file
+func Load(path string) (*Document, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	dir := filepath.Dir(path)
+	return Decode(f, func(uri string) (io.ReadCloser, error) {
+		return os.Open(filepath.Join(dir, filepath.FromSlash(uri)))
+	})
+}
+
+// Decode reads a glTF asset, the format (JSON or binary) is detected from the
+// data. open is used to read external resources given their relative URI, it
+// can be nil if the asset is self-contained
+func Decode(r io.Reader, open func(uri string) (io.ReadCloser, error)) (*Document, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var bin []byte
+	if bytes.HasPrefix(data, []byte("glTF")) {
+		if data, bin, err = splitGLB(data); err != nil {
+			return nil, err
+		}
+	}
+	var doc document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("gltf: %v", err)
+	}
+	if !strings.HasPrefix(doc.Asset.Version, "2.") {
+		return nil, fmt.Errorf("gltf: unsupported version %q", doc.Asset.Version)
+	}
+	if len(doc.ExtensionsRequired) > 0 {
+		return nil, fmt.Errorf("gltf: unsupported required extensions %v", doc.ExtensionsRequired)
+	}
+	imp := importer{doc: &doc, open: open, bin: bin}
+	if err := imp.run(); err != nil {
+		return nil, fmt.Errorf("gltf: %v", err)
+	}
+	return &imp.out, nil
+}
+
+// splitGLB returns the JSON and binary chunks of a .glb file
+func splitGLB(data []byte) (js, bin []byte, err error) {
+	if len(data) < 12 {
+		return nil, nil, errors.New("gltf: truncated header")
+	}
+	if v := binary.LittleEndian.Uint32(data[4:]); v != 2 {
+		return nil, nil, fmt.Errorf("gltf: unsupported container version %d", v)
+	}
+	if l := binary.LittleEndian.Uint32(data[8:]); int(l) < len(data) {
+		data = data[:l]
+	}
+	for off := 12; off+8 <= len(data); {
+		length := int(binary.LittleEndian.Uint32(data[off:]))
+		typ := string(data[off+4 : off+8])
+		off += 8
+		if off+length > len(data) {
+			return nil, nil, errors.New("gltf: truncated chunk")
+		}
+		switch typ {
+		case "JSON":
+			js = data[off : off+length]
+		case "BIN\x00":
+			bin = data[off : off+length]
+		}
+		off += length
+	}
+	if js == nil {
+		return nil, nil, errors.New("gltf: missing JSON chunk")
+	}
+	return js, bin, nil
+}
+
+// importer converts the document to the exported types
+type importer struct {
+	doc     *document
+	open    func(uri string) (io.ReadCloser, error)
+	bin     []byte   // Binary chunk of a .glb file
+	buffers [][]byte // Data of the buffers
+	out     Document
+}
+
+func (imp *importer) run() error {
+	steps := []func() error{
+		imp.loadBuffers, imp.loadSamplers, imp.loadTextures, imp.loadMaterials,
+		imp.loadAccessors, imp.loadMeshes, imp.loadCameras, imp.loadNodes, imp.loadScenes,
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readURI returns the data of a data URI or of an external file
+func (imp *importer) readURI(uri string) ([]byte, error) {
+	if strings.HasPrefix(uri, "data:") {
+		i := strings.Index(uri, ",")
+		if i < 0 || !strings.HasSuffix(uri[:i], ";base64") {
+			return nil, errors.New("unsupported data URI")
+		}
+		return base64.StdEncoding.DecodeString(uri[i+1:])
+	}
+	if imp.open == nil {
+		return nil, fmt.Errorf("external resource %q without a way to open it", uri)
+	}
+	path, err := url.PathUnescape(uri)
+	if err != nil {
+		return nil, err
+	}
+	f, err := imp.open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ioutil.ReadAll(f)
+}
+
+func (imp *importer) loadBuffers() error {
+	for i, b := range imp.doc.Buffers {
+		var data []byte
+		if b.URI == "" {
+			if i != 0 || imp.bin == nil {
+				return fmt.Errorf("buffer %d has no data", i)
+			}
+			data = imp.bin
+		} else {
+			var err error
+			if data, err = imp.readURI(b.URI); err != nil {
+				return fmt.Errorf("buffer %d: %v", i, err)
+			}
+		}
+		if b.ByteLength < 0 || len(data) < b.ByteLength {
+			return fmt.Errorf("buffer %d: expected %d bytes, got %d", i, b.ByteLength, len(data))
+		}
+		imp.buffers = append(imp.buffers, data[:b.ByteLength])
+	}
+	return nil
+}
+
+// view returns the data of a buffer view, without considering the stride
+func (imp *importer) view(index int) ([]byte, error) {
+	if index < 0 || index >= len(imp.doc.BufferViews) {
+		return nil, fmt.Errorf("invalid buffer view %d", index)
+	}
+	bv := imp.doc.BufferViews[index]
+	if bv.Buffer < 0 || bv.Buffer >= len(imp.buffers) {
+		return nil, fmt.Errorf("buffer view %d: invalid buffer %d", index, bv.Buffer)
+	}
+	buf := imp.buffers[bv.Buffer]
+	if bv.ByteOffset < 0 || bv.ByteLength < 0 || bv.ByteOffset+bv.ByteLength > len(buf) {
+		return nil, fmt.Errorf("buffer view %d out of range", index)
+	}
+	return buf[bv.ByteOffset : bv.ByteOffset+bv.ByteLength], nil
+}
+
+func (imp *importer) loadSamplers() error {
+	for _, js := range imp.doc.Samplers {
+		s := defaultSampler
+		if js.MagFilter != 0 {
+			s.MagFilter = js.MagFilter
+		}
+		if js.MinFilter != 0 {
+			s.MinFilter = js.MinFilter
+		}
+		if js.WrapS != 0 {
+			s.WrapS = js.WrapS
+		}
+		if js.WrapT != 0 {
+			s.WrapT = js.WrapT
+		}
+		imp.out.Samplers = append(imp.out.Samplers, &s)
+	}
+	return nil
+}
+
+func (imp *importer) loadTextures() error {
+	images := make([]image.Image, len(imp.doc.Images))
+	for i, ji := range imp.doc.Images {
+		var data []byte
+		var err error
+		if ji.BufferView != nil {
+			data, err = imp.view(*ji.BufferView)
+		} else {
+			data, err = imp.readURI(ji.URI)
+		}
+		if err == nil {
+			images[i], _, err = image.Decode(bytes.NewReader(data))
+		}
+		if err != nil {
+			return fmt.Errorf("image %d: %v", i, err)
+		}
+	}
+	for i, jt := range imp.doc.Textures {
+		// Each texture gets its own copy, so changing it does not affect others
+		s := defaultSampler
+		t := Texture{Name: jt.Name, Sampler: &s}
+		if jt.Source != nil {
+			if *jt.Source < 0 || *jt.Source >= len(images) {
+				return fmt.Errorf("texture %d: invalid image %d", i, *jt.Source)
+			}
+			t.Image = images[*jt.Source]
+		}
+		if jt.Sampler != nil {
+			if *jt.Sampler < 0 || *jt.Sampler >= len(imp.out.Samplers) {
+				return fmt.Errorf("texture %d: invalid sampler %d", i, *jt.Sampler)
+			}
+			t.Sampler = imp.out.Samplers[*jt.Sampler]
+		}
+		imp.out.Textures = append(imp.out.Textures, &t)
+	}
+	return nil
+}
+
+// textureRef resolves a reference to a texture, ti can be nil
+func (imp *importer) textureRef(ti *jsonTextureInfo) (*TextureRef, error) {
+	if ti == nil {
+		return nil, nil
+	}
+	if ti.Index < 0 || ti.Index >= len(imp.out.Textures) {
+		return nil, fmt.Errorf("invalid texture %d", ti.Index)
+	}
+	return &TextureRef{imp.out.Textures[ti.Index], ti.TexCoord}, nil
+}
+
+func (imp *importer) loadMaterials() error {
+	for i, jm := range imp.doc.Materials {
+		m := Material{
+			Name:              jm.Name,
+			BaseColorFactor:   [4]float32{1, 1, 1, 1},
+			MetallicFactor:    1,
+			RoughnessFactor:   1,
+			NormalScale:       1,
+			OcclusionStrength: 1,
+			EmissiveFactor:    jm.EmissiveFactor,
+			AlphaMode:         "OPAQUE",
+			AlphaCutoff:       0.5,
+			DoubleSided:       jm.DoubleSided,
+		}
+		if jm.AlphaMode != "" {
+			m.AlphaMode = jm.AlphaMode
+		}
+		if jm.AlphaCutoff != nil {
+			m.AlphaCutoff = *jm.AlphaCutoff
+		}
+		refs := []struct {
+			dst **TextureRef
+			src *jsonTextureInfo
+		}{
+			{&m.NormalTexture, jm.NormalTexture},
+			{&m.OcclusionTexture, jm.OcclusionTexture},
+			{&m.EmissiveTexture, jm.EmissiveTexture},
+		}
+		if pbr := jm.PBRMetallicRoughness; pbr != nil {
+			if pbr.BaseColorFactor != nil {
+				m.BaseColorFactor = *pbr.BaseColorFactor
+			}
+			if pbr.MetallicFactor != nil {
+				m.MetallicFactor = *pbr.MetallicFactor
+			}
+			if pbr.RoughnessFactor != nil {
+				m.RoughnessFactor = *pbr.RoughnessFactor
+			}
+			refs = append(refs, []struct {
+				dst **TextureRef
+				src *jsonTextureInfo
+			}{
+				{&m.BaseColorTexture, pbr.BaseColorTexture},
+				{&m.MetallicRoughnessTexture, pbr.MetallicRoughnessTexture},
+			}...)
+		}
+		for _, r := range refs {
+			ref, err := imp.textureRef(r.src)
+			if err != nil {
+				return fmt.Errorf("material %d: %v", i, err)
+			}
+			*r.dst = ref
+		}
+		// Scale and strength default to 1 when absent
+		if jm.NormalTexture != nil && jm.NormalTexture.Scale != 0 {
+			m.NormalScale = jm.NormalTexture.Scale
+		}
+		if jm.OcclusionTexture != nil && jm.OcclusionTexture.Strength != 0 {
+			m.OcclusionStrength = jm.OcclusionTexture.Strength
+		}
+		imp.out.Materials = append(imp.out.Materials, &m)
+	}
+	return nil
+}
+
+func (imp *importer) loadMeshes() error {
+	for i, jm := range imp.doc.Meshes {
+		m := Mesh{Name: jm.Name}
+		for _, jp := range jm.Primitives {
+			p := Primitive{Mode: primitiveTriangles, Attributes: make(map[string]*Accessor)}
+			if jp.Mode != nil {
+				p.Mode = *jp.Mode
+			}
+			for name, idx := range jp.Attributes {
+				if idx < 0 || idx >= len(imp.out.Accessors) {
+					return fmt.Errorf("mesh %d: attribute %s: invalid accessor %d", i, name, idx)
+				}
+				p.Attributes[name] = imp.out.Accessors[idx]
+			}
+			if jp.Indices != nil {
+				if *jp.Indices < 0 || *jp.Indices >= len(imp.out.Accessors) {
+					return fmt.Errorf("mesh %d: invalid indices accessor %d", i, *jp.Indices)
+				}
+				p.Indices = imp.out.Accessors[*jp.Indices]
+			}
+			if jp.Material != nil {
+				if *jp.Material < 0 || *jp.Material >= len(imp.out.Materials) {
+					return fmt.Errorf("mesh %d: invalid material %d", i, *jp.Material)
+				}
+				p.Material = imp.out.Materials[*jp.Material]
+			}
+			m.Primitives = append(m.Primitives, &p)
+		}
+		imp.out.Meshes = append(imp.out.Meshes, &m)
+	}
+	return nil
+}
+
+func (imp *importer) loadCameras() error {
+	for i, jc := range imp.doc.Cameras {
+		c := Camera{Name: jc.Name}
+		switch {
+		case jc.Type == "perspective" && jc.Perspective != nil:
+			p := jc.Perspective
+			c.Perspective = true
+			c.AspectRatio, c.YFov, c.ZNear, c.ZFar = p.AspectRatio, p.YFov, p.ZNear, p.ZFar
+		case jc.Type == "orthographic" && jc.Orthographic != nil:
+			o := jc.Orthographic
+			c.XMag, c.YMag, c.ZNear, c.ZFar = o.XMag, o.YMag, o.ZNear, o.ZFar
+		default:
+			return fmt.Errorf("camera %d: invalid type %q", i, jc.Type)
+		}
+		imp.out.Cameras = append(imp.out.Cameras, &c)
+	}
+	return nil
+}
+
+func (imp *importer) loadScenes() error {
+	for i, js := range imp.doc.Scenes {
+		s := Scene{Name: js.Name}
+		for _, n := range js.Nodes {
+			if n < 0 || n >= len(imp.out.Nodes) {
+				return fmt.Errorf("scene %d: invalid node %d", i, n)
+			}
+			s.Nodes = append(s.Nodes, imp.out.Nodes[n])
+		}
+		imp.out.Scenes = append(imp.out.Scenes, &s)
+	}
+	switch {
+	case imp.doc.Scene != nil:
+		if *imp.doc.Scene < 0 || *imp.doc.Scene >= len(imp.out.Scenes) {
+			return fmt.Errorf("invalid scene %d", *imp.doc.Scene)
+		}
+		imp.out.Scene = imp.out.Scenes[*imp.doc.Scene]
+	case len(imp.out.Scenes) > 0:
+		imp.out.Scene = imp.out.Scenes[0]
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package gltf
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/png"
+	"math"
+	"strings"
+	"testing"
+)
+
+// dataURI returns a data URI with the bytes
+func dataURI(data []byte) string {
+	return "data:application/octet-stream;base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+// floats returns the bytes of float32 values in little endian
+func floats(v ...float32) []byte {
+	b := make([]byte, 4*len(v))
+	for i, f := range v {
+		binary.LittleEndian.PutUint32(b[i*4:], math.Float32bits(f))
+	}
+	return b
+}
+
+// decodeAccessors decodes a document with a single buffer, the given buffer
+// views and accessors (JSON arrays)
+func decodeAccessors(buf []byte, views, accessors string) (*Document, error) {
+	doc := fmt.Sprintf(`{
+		"asset": {"version": "2.0"},
+		"buffers": [{"uri": %q, "byteLength": %d}],
+		"bufferViews": %s,
+		"accessors": %s
+	}`, dataURI(buf), len(buf), views, accessors)
+	return Decode(strings.NewReader(doc), nil)
+}
+
+func TestAccessor(t *testing.T) {
+	// Three VEC2 interleaved with a float of padding, then a sparse part
+	buf := floats(1, 2, 0, 3, 4, 0, 5, 6, 0)
+	buf = append(buf, 2, 0, 0, 0) // Sparse index (uint16) and padding
+	buf = append(buf, floats(7, 8)...)
+	views := `[
+		{"buffer": 0, "byteOffset": 0, "byteLength": 36, "byteStride": 12},
+		{"buffer": 0, "byteOffset": 36, "byteLength": 4},
+		{"buffer": 0, "byteOffset": 40, "byteLength": 8}
+	]`
+	sparse := `"sparse": {"count": 1,
+		"indices": {"bufferView": 1, "componentType": 5123},
+		"values": {"bufferView": 2}}`
+	tests := []struct {
+		name     string
+		accessor string
+		want     []float32
+	}{
+		{"Strided", `{"bufferView": 0, "componentType": 5126, "count": 3, "type": "VEC2"}`, []float32{1, 2, 3, 4, 5, 6}},
+		{"Offset", `{"bufferView": 0, "byteOffset": 12, "componentType": 5126, "count": 2, "type": "VEC2"}`, []float32{3, 4, 5, 6}},
+		{"Sparse", `{"bufferView": 0, "componentType": 5126, "count": 3, "type": "VEC2", ` + sparse + `}`, []float32{1, 2, 3, 4, 7, 8}},
+		{"SparseZeros", `{"componentType": 5126, "count": 3, "type": "VEC2", ` + sparse + `}`, []float32{0, 0, 0, 0, 7, 8}},
+		{"Zeros", `{"componentType": 5126, "count": 2, "type": "SCALAR"}`, []float32{0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := decodeAccessors(buf, views, "["+tt.accessor+"]")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := doc.Accessors[0].Floats(); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMalformedAccessor(t *testing.T) {
+	buf := floats(1, 2, 3, 4)
+	buf = append(buf, 5, 0, 0, 0)
+	const views = `[
+		{"buffer": 0, "byteOffset": 0, "byteLength": 16},
+		{"buffer": 0, "byteOffset": 16, "byteLength": 4},
+		{"buffer": 0, "byteOffset": 0, "byteLength": 16, "byteStride": -8},
+		{"buffer": 0, "byteOffset": 0, "byteLength": 16, "byteStride": 4},
+		{"buffer": 0, "byteOffset": 0, "byteLength": 16, "byteStride": 4611686018427387904}
+	]`
+	tests := []struct {
+		name, accessor, err string
+	}{
+		{"ComponentType", `{"bufferView": 0, "componentType": 1, "count": 1, "type": "SCALAR"}`, "invalid component type 1"},
+		{"Type", `{"bufferView": 0, "componentType": 5126, "count": 1, "type": "VEC5"}`, `invalid type "VEC5"`},
+		{"NegativeCount", `{"bufferView": 0, "componentType": 5126, "count": -1, "type": "SCALAR"}`, "invalid count -1"},
+		{"HugeCount", `{"componentType": 5126, "count": 4611686018427387904, "type": "MAT4"}`, "too large"},
+		{"NegativeOffset", `{"bufferView": 0, "byteOffset": -4, "componentType": 5126, "count": 1, "type": "SCALAR"}`, "invalid byte offset -4"},
+		{"OffsetOutOfRange", `{"bufferView": 0, "byteOffset": 20, "componentType": 5126, "count": 1, "type": "SCALAR"}`, "out of range"},
+		{"CountOutOfRange", `{"bufferView": 0, "componentType": 5126, "count": 3, "type": "VEC2"}`, "out of range"},
+		{"BufferView", `{"bufferView": 9, "componentType": 5126, "count": 1, "type": "SCALAR"}`, "invalid buffer view 9"},
+		{"NegativeStride", `{"bufferView": 2, "componentType": 5126, "count": 1, "type": "SCALAR"}`, "byte stride -8 smaller"},
+		{"SmallStride", `{"bufferView": 3, "componentType": 5126, "count": 2, "type": "VEC2"}`, "byte stride 4 smaller"},
+		{"HugeStride", `{"bufferView": 4, "componentType": 5126, "count": 3, "type": "SCALAR"}`, "out of range"},
+		{"SparseIndex", sparse(`"count": 1, "indices": {"bufferView": 1, "componentType": 5121}, "values": {"bufferView": 0}`), "index 5 out of range"},
+		{"SparseIndexType", sparse(`"count": 1, "indices": {"bufferView": 1, "componentType": 5126}, "values": {"bufferView": 0}`), "invalid indices component type"},
+		{"SparseCount", sparse(`"count": 5, "indices": {"bufferView": 1, "componentType": 5121}, "values": {"bufferView": 0}`), "invalid count 5"},
+		{"SparseZeroCount", sparse(`"count": 0, "indices": {"bufferView": 1, "componentType": 5121}, "values": {"bufferView": 0}`), "invalid count 0"},
+		{"SparseIndicesRange", sparse(`"count": 2, "indices": {"bufferView": 1, "byteOffset": 3, "componentType": 5121}, "values": {"bufferView": 0}`), "out of range"},
+		{"SparseValuesRange", sparse(`"count": 1, "indices": {"bufferView": 1, "componentType": 5121}, "values": {"bufferView": 0, "byteOffset": 14}`), "out of range"},
+		{"SparseNegativeOffset", sparse(`"count": 1, "indices": {"bufferView": 1, "componentType": 5121}, "values": {"bufferView": 0, "byteOffset": -4}`), "invalid byte offset"},
+		{"SparseBufferView", sparse(`"count": 1, "indices": {"bufferView": -1, "componentType": 5121}, "values": {"bufferView": 0}`), "invalid buffer view -1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeAccessors(buf, views, "["+tt.accessor+"]")
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("got error %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+// sparse returns an accessor of 4 floats with the given sparse part
+func sparse(s string) string {
+	return `{"bufferView": 0, "componentType": 5126, "count": 4, "type": "SCALAR", "sparse": {` + s + `}}`
+}
+
+func TestMalformedDocument(t *testing.T) {
+	tests := []struct {
+		name, doc, err string
+	}{
+		{"JSON", `{"asset": `, "gltf: unexpected end of JSON input"},
+		{"Version", `{"asset": {"version": "1.0"}}`, `unsupported version "1.0"`},
+		{"Extensions", `{"asset": {"version": "2.0"}, "extensionsRequired": ["KHR_draco_mesh_compression"]}`, "unsupported required extensions"},
+		{"NoBufferData", `{"asset": {"version": "2.0"}, "buffers": [{"byteLength": 4}]}`, "buffer 0 has no data"},
+		{"ShortBuffer", `{"asset": {"version": "2.0"}, "buffers": [{"uri": "` + dataURI([]byte{1, 2}) + `", "byteLength": 4}]}`, "expected 4 bytes, got 2"},
+		{"NegativeLength", `{"asset": {"version": "2.0"}, "buffers": [{"uri": "` + dataURI([]byte{1, 2}) + `", "byteLength": -1}]}`, "expected -1 bytes"},
+		{"DataURI", `{"asset": {"version": "2.0"}, "buffers": [{"uri": "data:text/plain,abc", "byteLength": 3}]}`, "unsupported data URI"},
+		{"ExternalURI", `{"asset": {"version": "2.0"}, "buffers": [{"uri": "data.bin", "byteLength": 3}]}`, "without a way to open it"},
+		{"ViewRange", `{"asset": {"version": "2.0"}, "buffers": [{"uri": "` + dataURI([]byte{1, 2}) + `", "byteLength": 2}],
+			"bufferViews": [{"buffer": 0, "byteOffset": 1, "byteLength": 2}],
+			"accessors": [{"bufferView": 0, "componentType": 5121, "count": 1, "type": "SCALAR"}]}`, "buffer view 0 out of range"},
+		{"GLB", "glTF\x01\x00\x00\x00", "truncated header"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(strings.NewReader(tt.doc), nil)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("got error %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestDefaultSampler(t *testing.T) {
+	var img bytes.Buffer
+	if err := png.Encode(&img, image.NewGray(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := Decode(strings.NewReader(`{
+		"asset": {"version": "2.0"},
+		"images": [{"uri": "data:image/png;base64,`+base64.StdEncoding.EncodeToString(img.Bytes())+`"}],
+		"textures": [{"source": 0}, {"source": 0}]
+	}`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := doc.Textures[0].Sampler, doc.Textures[1].Sampler
+	if a == b {
+		t.Fatal("textures share the default sampler")
+	}
+	a.WrapS = ClampToEdge
+	if *b != defaultSampler || b.WrapS != Repeat {
+		t.Errorf("changing a sampler changed another: %+v", *b)
+	}
+}
@@ -0,0 +1,151 @@
+package gltf
+
+// Types in this file mirror the JSON schema of glTF 2.0, only the properties
+// used by the importer are declared. Indices are pointers when optional
+
+type document struct {
+	Asset struct {
+		Version    string `json:"version"`
+		MinVersion string `json:"minVersion"`
+	} `json:"asset"`
+	Scene       *int             `json:"scene"`
+	Scenes      []jsonScene      `json:"scenes"`
+	Nodes       []jsonNode       `json:"nodes"`
+	Meshes      []jsonMesh       `json:"meshes"`
+	Accessors   []jsonAccessor   `json:"accessors"`
+	BufferViews []jsonBufferView `json:"bufferViews"`
+	Buffers     []jsonBuffer     `json:"buffers"`
+	Materials   []jsonMaterial   `json:"materials"`
+	Textures    []jsonTexture    `json:"textures"`
+	Images      []jsonImage      `json:"images"`
+	Samplers    []jsonSampler    `json:"samplers"`
+	Cameras     []jsonCamera     `json:"cameras"`
+
+	ExtensionsRequired []string `json:"extensionsRequired"`
+}
+
+type jsonScene struct {
+	Name  string `json:"name"`
+	Nodes []int  `json:"nodes"`
+}
+
+type jsonNode struct {
+	Name        string       `json:"name"`
+	Children    []int        `json:"children"`
+	Mesh        *int         `json:"mesh"`
+	Camera      *int         `json:"camera"`
+	Matrix      *[16]float32 `json:"matrix"`
+	Translation *[3]float32  `json:"translation"`
+	Rotation    *[4]float32  `json:"rotation"`
+	Scale       *[3]float32  `json:"scale"`
+}
+
+type jsonMesh struct {
+	Name       string          `json:"name"`
+	Primitives []jsonPrimitive `json:"primitives"`
+}
+
+type jsonPrimitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    *int           `json:"indices"`
+	Material   *int           `json:"material"`
+	Mode       *uint32        `json:"mode"`
+}
+
+type jsonAccessor struct {
+	BufferView    *int      `json:"bufferView"`
+	ByteOffset    int       `json:"byteOffset"`
+	ComponentType uint32    `json:"componentType"`
+	Normalized    bool      `json:"normalized"`
+	Count         int       `json:"count"`
+	Type          string    `json:"type"`
+	Min           []float64 `json:"min"`
+	Max           []float64 `json:"max"`
+	Name          string    `json:"name"`
+	Sparse        *struct {
+		Count   int `json:"count"`
+		Indices struct {
+			BufferView    int    `json:"bufferView"`
+			ByteOffset    int    `json:"byteOffset"`
+			ComponentType uint32 `json:"componentType"`
+		} `json:"indices"`
+		Values struct {
+			BufferView int `json:"bufferView"`
+			ByteOffset int `json:"byteOffset"`
+		} `json:"values"`
+	} `json:"sparse"`
+}
+
+type jsonBufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset"`
+	ByteLength int `json:"byteLength"`
+	ByteStride int `json:"byteStride"`
+}
+
+type jsonBuffer struct {
+	URI        string `json:"uri"`
+	ByteLength int    `json:"byteLength"`
+}
+
+type jsonTextureInfo struct {
+	Index    int     `json:"index"`
+	TexCoord int     `json:"texCoord"`
+	Scale    float32 `json:"scale"`    // Only for normal textures
+	Strength float32 `json:"strength"` // Only for occlusion textures
+}
+
+type jsonMaterial struct {
+	Name                 string `json:"name"`
+	PBRMetallicRoughness *struct {
+		BaseColorFactor          *[4]float32      `json:"baseColorFactor"`
+		BaseColorTexture         *jsonTextureInfo `json:"baseColorTexture"`
+		MetallicFactor           *float32         `json:"metallicFactor"`
+		RoughnessFactor          *float32         `json:"roughnessFactor"`
+		MetallicRoughnessTexture *jsonTextureInfo `json:"metallicRoughnessTexture"`
+	} `json:"pbrMetallicRoughness"`
+	NormalTexture    *jsonTextureInfo `json:"normalTexture"`
+	OcclusionTexture *jsonTextureInfo `json:"occlusionTexture"`
+	EmissiveTexture  *jsonTextureInfo `json:"emissiveTexture"`
+	EmissiveFactor   [3]float32       `json:"emissiveFactor"`
+	AlphaMode        string           `json:"alphaMode"`
+	AlphaCutoff      *float32         `json:"alphaCutoff"`
+	DoubleSided      bool             `json:"doubleSided"`
+}
+
+type jsonTexture struct {
+	Name    string `json:"name"`
+	Sampler *int   `json:"sampler"`
+	Source  *int   `json:"source"`
+}
+
+type jsonImage struct {
+	Name       string `json:"name"`
+	URI        string `json:"uri"`
+	MimeType   string `json:"mimeType"`
+	BufferView *int   `json:"bufferView"`
+}
+
+type jsonSampler struct {
+	MagFilter int32 `json:"magFilter"`
+	MinFilter int32 `json:"minFilter"`
+	WrapS     int32 `json:"wrapS"`
+	WrapT     int32 `json:"wrapT"`
+}
+
+type jsonCamera struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Perspective *struct {
+		AspectRatio float32 `json:"aspectRatio"`
+		YFov        float32 `json:"yfov"`
+		ZNear       float32 `json:"znear"`
+		ZFar        float32 `json:"zfar"`
+	} `json:"perspective"`
+	Orthographic *struct {
+		XMag  float32 `json:"xmag"`
+		YMag  float32 `json:"ymag"`
+		ZNear float32 `json:"znear"`
+		ZFar  float32 `json:"zfar"`
+	} `json:"orthographic"`
+}
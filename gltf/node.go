@@ -0,0 +1,209 @@
+package gltf
+
+import (
+	"fmt"
+	"math"
+)
+
+// Node is an element of the scene hierarchy, with a transform relative to
+// its parent. Matrices are 4x4 in column-major order, as expected by GL
+type Node struct {
+	Name        string
+	Parent      *Node // nil for root nodes
+	Children    []*Node
+	Mesh        *Mesh   // nil if the node has no mesh
+	Camera      *Camera // nil if the node has no camera
+	Translation [3]float32
+	Rotation    [4]float32 // Unit quaternion, as X, Y, Z, W
+	Scale       [3]float32
+}
+
+// Camera describes a projection, the camera looks toward -Z of its node
+type Camera struct {
+	Name        string
+	Perspective bool    // True for perspective, false for orthographic
+	AspectRatio float32 // Perspective only, 0 to use the aspect of the viewport
+	YFov        float32 // Perspective only, vertical field of view in radians
+	XMag, YMag  float32 // Orthographic only, half width and height of the view
+	ZNear, ZFar float32 // ZFar is 0 for an infinite perspective projection
+}
+
+// Local returns the transform of the node relative to its parent
+func (n *Node) Local() [16]float32 {
+	x, y, z, w := float64(n.Rotation[0]), float64(n.Rotation[1]), float64(n.Rotation[2]), float64(n.Rotation[3])
+	sx, sy, sz := n.Scale[0], n.Scale[1], n.Scale[2]
+	return [16]float32{
+		float32(1-2*(y*y+z*z)) * sx, float32(2*(x*y+z*w)) * sx, float32(2*(x*z-y*w)) * sx, 0,
+		float32(2*(x*y-z*w)) * sy, float32(1-2*(x*x+z*z)) * sy, float32(2*(y*z+x*w)) * sy, 0,
+		float32(2*(x*z+y*w)) * sz, float32(2*(y*z-x*w)) * sz, float32(1-2*(x*x+y*y)) * sz, 0,
+		n.Translation[0], n.Translation[1], n.Translation[2], 1,
+	}
+}
+
+// World returns the transform of the node relative to the scene
+func (n *Node) World() [16]float32 {
+	if n.Parent == nil {
+		return n.Local()
+	}
+	return mul(n.Parent.World(), n.Local())
+}
+
+// Walk calls fn for the node and all its descendants, depth first, with the
+// world transform of each node
+func (n *Node) Walk(fn func(node *Node, world [16]float32)) {
+	var walk func(n *Node, parent [16]float32)
+	walk = func(n *Node, parent [16]float32) {
+		world := mul(parent, n.Local())
+		fn(n, world)
+		for _, c := range n.Children {
+			walk(c, world)
+		}
+	}
+	if n.Parent == nil {
+		walk(n, identity)
+	} else {
+		walk(n, n.Parent.World())
+	}
+}
+
+// Projection returns the projection matrix of the camera, aspect is the
+// ratio width / height of the viewport, used if AspectRatio is 0
+func (c *Camera) Projection(aspect float32) [16]float32 {
+	n, f := c.ZNear, c.ZFar
+	if !c.Perspective {
+		return [16]float32{
+			1 / c.XMag, 0, 0, 0,
+			0, 1 / c.YMag, 0, 0,
+			0, 0, 2 / (n - f), 0,
+			0, 0, (f + n) / (n - f), 1,
+		}
+	}
+	if c.AspectRatio != 0 {
+		aspect = c.AspectRatio
+	}
+	t := float32(1 / math.Tan(float64(c.YFov)/2))
+	p := [16]float32{
+		t / aspect, 0, 0, 0,
+		0, t, 0, 0,
+		0, 0, -1, -1,
+		0, 0, -2 * n, 0,
+	}
+	if f != 0 {
+		p[10] = (f + n) / (n - f)
+		p[14] = 2 * f * n / (n - f)
+	}
+	return p
+}
+
+var identity = [16]float32{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}
+
+// mul returns the product a * b of column-major matrices
+func mul(a, b [16]float32) [16]float32 {
+	var m [16]float32
+	for c := 0; c < 4; c++ {
+		for r := 0; r < 4; r++ {
+			var s float32
+			for k := 0; k < 4; k++ {
+				s += a[k*4+r] * b[c*4+k]
+			}
+			m[c*4+r] = s
+		}
+	}
+	return m
+}
+
+// decompose sets translation, rotation and scale from a matrix
+// The matrix is assumed to have no shear, as required by the spec
+func (n *Node) decompose(m [16]float32) {
+	n.Translation = [3]float32{m[12], m[13], m[14]}
+	var cols [3][3]float64
+	for c := 0; c < 3; c++ {
+		v := [3]float64{float64(m[c*4]), float64(m[c*4+1]), float64(m[c*4+2])}
+		s := math.Sqrt(v[0]*v[0] + v[1]*v[1] + v[2]*v[2])
+		n.Scale[c] = float32(s)
+		if s != 0 {
+			v[0], v[1], v[2] = v[0]/s, v[1]/s, v[2]/s
+		}
+		cols[c] = v
+	}
+	// A negative determinant is a mirroring, moved to the X scale
+	det := cols[0][0]*(cols[1][1]*cols[2][2]-cols[2][1]*cols[1][2]) -
+		cols[1][0]*(cols[0][1]*cols[2][2]-cols[2][1]*cols[0][2]) +
+		cols[2][0]*(cols[0][1]*cols[1][2]-cols[1][1]*cols[0][2])
+	if det < 0 {
+		n.Scale[0] = -n.Scale[0]
+		cols[0] = [3]float64{-cols[0][0], -cols[0][1], -cols[0][2]}
+	}
+	// Rotation matrix to quaternion, element (r, c) is cols[c][r]
+	var x, y, z, w float64
+	switch tr := cols[0][0] + cols[1][1] + cols[2][2]; {
+	case tr > 0:
+		s := 0.5 / math.Sqrt(tr+1)
+		w, x, y, z = 0.25/s, (cols[1][2]-cols[2][1])*s, (cols[2][0]-cols[0][2])*s, (cols[0][1]-cols[1][0])*s
+	case cols[0][0] > cols[1][1] && cols[0][0] > cols[2][2]:
+		s := 2 * math.Sqrt(1+cols[0][0]-cols[1][1]-cols[2][2])
+		w, x, y, z = (cols[1][2]-cols[2][1])/s, 0.25*s, (cols[1][0]+cols[0][1])/s, (cols[2][0]+cols[0][2])/s
+	case cols[1][1] > cols[2][2]:
+		s := 2 * math.Sqrt(1+cols[1][1]-cols[0][0]-cols[2][2])
+		w, x, y, z = (cols[2][0]-cols[0][2])/s, (cols[1][0]+cols[0][1])/s, 0.25*s, (cols[2][1]+cols[1][2])/s
+	default:
+		s := 2 * math.Sqrt(1+cols[2][2]-cols[0][0]-cols[1][1])
+		w, x, y, z = (cols[0][1]-cols[1][0])/s, (cols[2][0]+cols[0][2])/s, (cols[2][1]+cols[1][2])/s, 0.25*s
+	}
+	n.Rotation = [4]float32{float32(x), float32(y), float32(z), float32(w)}
+}
+
+func (imp *importer) loadNodes() error {
+	for i, jn := range imp.doc.Nodes {
+		n := Node{Name: jn.Name, Rotation: [4]float32{0, 0, 0, 1}, Scale: [3]float32{1, 1, 1}}
+		if jn.Matrix != nil {
+			n.decompose(*jn.Matrix)
+		}
+		if jn.Translation != nil {
+			n.Translation = *jn.Translation
+		}
+		if jn.Rotation != nil {
+			n.Rotation = *jn.Rotation
+		}
+		if jn.Scale != nil {
+			n.Scale = *jn.Scale
+		}
+		if jn.Mesh != nil {
+			if *jn.Mesh < 0 || *jn.Mesh >= len(imp.out.Meshes) {
+				return fmt.Errorf("node %d: invalid mesh %d", i, *jn.Mesh)
+			}
+			n.Mesh = imp.out.Meshes[*jn.Mesh]
+		}
+		if jn.Camera != nil {
+			if *jn.Camera < 0 || *jn.Camera >= len(imp.out.Cameras) {
+				return fmt.Errorf("node %d: invalid camera %d", i, *jn.Camera)
+			}
+			n.Camera = imp.out.Cameras[*jn.Camera]
+		}
+		imp.out.Nodes = append(imp.out.Nodes, &n)
+	}
+	for i, jn := range imp.doc.Nodes {
+		n := imp.out.Nodes[i]
+		for _, c := range jn.Children {
+			if c < 0 || c >= len(imp.out.Nodes) {
+				return fmt.Errorf("node %d: invalid child %d", i, c)
+			}
+			child := imp.out.Nodes[c]
+			if child.Parent != nil || child == n {
+				return fmt.Errorf("node %d: child %d already has a parent", i, c)
+			}
+			child.Parent = n
+			n.Children = append(n.Children, child)
+		}
+	}
+	// Every node has at most one parent, a cycle would have no root
+	for i, n := range imp.out.Nodes {
+		steps := 0
+		for p := n.Parent; p != nil; p = p.Parent {
+			if steps++; steps > len(imp.out.Nodes) {
+				return fmt.Errorf("node %d: cycle in the hierarchy", i)
+			}
+		}
+	}
+	return nil
+}
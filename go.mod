@@ -1,6 +1,6 @@
 module github.com/akiross/go-glad
 
-go 1.12
+go 1.16
 
 require (
 	github.com/fogleman/gg v1.3.0
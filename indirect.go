@@ -0,0 +1,140 @@
+package glad
+
+import (
+	"unsafe"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+)
+
+// Indirect drawing reads the parameters of the draw calls from a buffer bound
+// to gl.DRAW_INDIRECT_BUFFER. With the MultiDraw functions, many meshes stored
+// in the same VAO (e.g. merged in a single VBO) can be drawn with one call
+
+// DrawArraysIndirectCommand holds the parameters of one DrawArrays command
+// The layout matches the one expected by GL in the indirect buffer
+type DrawArraysIndirectCommand struct {
+	Count         uint32 // Number of vertices to draw
+	InstanceCount uint32 // Number of instances to draw
+	First         uint32 // First vertex to draw
+	BaseInstance  uint32 // First instance used to fetch instanced attributes
+}
+
+// DrawElementsIndirectCommand holds the parameters of one DrawElements command
+// The layout matches the one expected by GL in the indirect buffer
+type DrawElementsIndirectCommand struct {
+	Count         uint32 // Number of elements to draw
+	InstanceCount uint32 // Number of instances to draw
+	FirstIndex    uint32 // First index to read from the element buffer
+	BaseVertex    int32  // Added to each index
+	BaseInstance  uint32 // First instance used to fetch instanced attributes
+}
+
+// MultiDrawArraysIndirect draws drawCount commands of type
+// DrawArraysIndirectCommand, read from the buffer bound to
+// gl.DRAW_INDIRECT_BUFFER starting at offset bytes
+// stride is the distance in bytes between commands, 0 if tightly packed
+func MultiDrawArraysIndirect(mode uint32, offset int, drawCount, stride int32) {
+	checkThread()
+	gl.MultiDrawArraysIndirect(mode, gl.PtrOffset(offset), drawCount, stride)
+}
+
+// MultiDrawElementsIndirect is like MultiDrawArraysIndirect, with commands of
+// type DrawElementsIndirectCommand. indexType is the type of the elements in
+// the element buffer, e.g. gl.UNSIGNED_INT
+func MultiDrawElementsIndirect(mode, indexType uint32, offset int, drawCount, stride int32) {
+	checkThread()
+	gl.MultiDrawElementsIndirect(mode, indexType, gl.PtrOffset(offset), drawCount, stride)
+}
+
+// MultiDrawArraysIndirectCount is like MultiDrawArraysIndirect, but the number
+// of commands is read from the buffer bound to gl.PARAMETER_BUFFER_ARB, at
+// countOffset bytes, and clamped to maxDrawCount. This allows the GPU itself
+// (e.g. a compute shader) to decide how many draws to perform
+// Requires GL 4.6 or ARB_indirect_parameters
+func MultiDrawArraysIndirectCount(mode uint32, offset, countOffset int, maxDrawCount, stride int32) {
+	checkThread()
+	gl.MultiDrawArraysIndirectCountARB(mode, gl.PtrOffset(offset), countOffset, maxDrawCount, stride)
+}
+
+// MultiDrawElementsIndirectCount is like MultiDrawArraysIndirectCount, for
+// commands of type DrawElementsIndirectCommand
+// Requires GL 4.6 or ARB_indirect_parameters
+func MultiDrawElementsIndirectCount(mode, indexType uint32, offset, countOffset int, maxDrawCount, stride int32) {
+	checkThread()
+	gl.MultiDrawElementsIndirectCountARB(mode, indexType, gl.PtrOffset(offset), countOffset, maxDrawCount, stride)
+}
+
+// IndirectBuffer is a buffer of draw commands built on the CPU
+// Commands are added with AddArrays or AddElements (do not mix them in the
+// same buffer), uploaded with Upload and drawn with Draw
+type IndirectBuffer struct {
+	VertexBufferObject
+	arrays   []DrawArraysIndirectCommand
+	elements []DrawElementsIndirectCommand
+	uploaded int32 // Number of commands in the GL buffer
+	indexed  bool  // The commands in the GL buffer are elements commands
+}
+
+// NewIndirectBuffer creates an empty buffer of commands
+func NewIndirectBuffer() *IndirectBuffer {
+	return &IndirectBuffer{VertexBufferObject: NewVertexBufferObject()}
+}
+
+// AddArrays appends a DrawArrays command
+func (ib *IndirectBuffer) AddArrays(cmd DrawArraysIndirectCommand) {
+	if len(ib.elements) > 0 {
+		panic("IndirectBuffer cannot mix arrays and elements commands")
+	}
+	ib.arrays = append(ib.arrays, cmd)
+}
+
+// AddElements appends a DrawElements command
+func (ib *IndirectBuffer) AddElements(cmd DrawElementsIndirectCommand) {
+	if len(ib.arrays) > 0 {
+		panic("IndirectBuffer cannot mix arrays and elements commands")
+	}
+	ib.elements = append(ib.elements, cmd)
+}
+
+// Reset removes all the commands on the CPU side, the uploaded ones are kept
+func (ib *IndirectBuffer) Reset() {
+	ib.arrays = ib.arrays[:0]
+	ib.elements = ib.elements[:0]
+}
+
+// Len returns the number of commands on the CPU side
+func (ib *IndirectBuffer) Len() int {
+	return len(ib.arrays) + len(ib.elements)
+}
+
+// Upload copies the commands to the GL buffer, see BufferData32 for usage
+func (ib *IndirectBuffer) Upload(usage uint32) {
+	checkThread()
+	var size int
+	var ptr unsafe.Pointer
+	switch {
+	case len(ib.arrays) > 0:
+		size = len(ib.arrays) * int(unsafe.Sizeof(ib.arrays[0]))
+		ptr = unsafe.Pointer(&ib.arrays[0])
+	case len(ib.elements) > 0:
+		size = len(ib.elements) * int(unsafe.Sizeof(ib.elements[0]))
+		ptr = unsafe.Pointer(&ib.elements[0])
+	}
+	gl.NamedBufferData(uint32(ib.VertexBufferObject), size, ptr, usage)
+	registry.resize("VertexBufferObject", uint32(ib.VertexBufferObject), size)
+	ib.uploaded = int32(ib.Len())
+	ib.indexed = len(ib.elements) > 0
+}
+
+// Draw binds the buffer to gl.DRAW_INDIRECT_BUFFER and executes all the
+// uploaded commands with a single call. indexType is used only for elements
+// commands. The VAO and program to use must be already bound
+func (ib *IndirectBuffer) Draw(mode, indexType uint32) {
+	checkThread()
+	ib.Bind(gl.DRAW_INDIRECT_BUFFER)
+	if ib.indexed {
+		MultiDrawElementsIndirect(mode, indexType, 0, ib.uploaded, 0)
+	} else {
+		MultiDrawArraysIndirect(mode, 0, ib.uploaded, 0)
+	}
+}
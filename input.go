@@ -0,0 +1,256 @@
+package glad
+
+import "github.com/go-gl/glfw/v3.2/glfw"
+
+// Event is an input event, one of the *Event types of this package
+type Event interface {
+	event()
+}
+
+// KeyEvent is sent when a key is pressed, repeated or released
+type KeyEvent struct {
+	Key      glfw.Key
+	Scancode int
+	Action   glfw.Action
+	Mods     glfw.ModifierKey
+}
+
+// CharEvent is sent when a character is typed, for text input
+type CharEvent struct {
+	Char rune
+}
+
+// MouseButtonEvent is sent when a mouse button is pressed or released
+type MouseButtonEvent struct {
+	Button glfw.MouseButton
+	Action glfw.Action
+	Mods   glfw.ModifierKey
+}
+
+// CursorEvent is sent when the cursor moves, in screen coordinates
+type CursorEvent struct {
+	X, Y float64
+}
+
+// ScrollEvent is sent when the wheel (or the touchpad) scrolls
+type ScrollEvent struct {
+	X, Y float64
+}
+
+// ResizeEvent is sent when the window is resized, in screen coordinates
+type ResizeEvent struct {
+	Width, Height int
+}
+
+// FramebufferResizeEvent is sent when the framebuffer is resized, in pixels
+type FramebufferResizeEvent struct {
+	Width, Height int
+}
+
+// DropEvent is sent when files are dropped on the window
+type DropEvent struct {
+	Paths []string
+}
+
+func (KeyEvent) event()               {}
+func (CharEvent) event()              {}
+func (MouseButtonEvent) event()       {}
+func (CursorEvent) event()            {}
+func (ScrollEvent) event()            {}
+func (ResizeEvent) event()            {}
+func (FramebufferResizeEvent) event() {}
+func (DropEvent) event()              {}
+
+// Trigger is a key or a mouse button that can be bound to an action
+type Trigger struct {
+	Key    glfw.Key
+	Button glfw.MouseButton
+	Mouse  bool // True for a mouse button, false for a key
+}
+
+// KeyTrigger returns a trigger for a key
+func KeyTrigger(key glfw.Key) Trigger {
+	return Trigger{Key: key}
+}
+
+// ButtonTrigger returns a trigger for a mouse button
+func ButtonTrigger(button glfw.MouseButton) Trigger {
+	return Trigger{Button: button, Mouse: true}
+}
+
+// Input collects the events of a window in a queue for each frame and keeps
+// the state of keys and mouse buttons. Actions are names bound to keys or
+// buttons, so that the application can be independent of the actual inputs
+// and let the user rebind them
+type Input struct {
+	events   []Event
+	down     map[Trigger]bool
+	pressed  map[Trigger]bool // Went down during this frame
+	released map[Trigger]bool // Went up during this frame
+	actions  map[string][]Trigger
+
+	cursorX, cursorY float64
+	deltaX, deltaY   float64
+	scrollX, scrollY float64
+	cursorValid      bool
+}
+
+// Input returns the input of the window, creating it the first time
+// This installs all the input callbacks of the window (key, char, mouse
+// button, cursor position, scroll, size, framebuffer size and drop), which
+// must not be replaced afterwards. The input advances to a new frame when
+// PollEvents or WaitEvents of the window are called
+func (w *Window) Input() *Input {
+	if w.input == nil {
+		w.input = newInput()
+		w.input.install(w.Window)
+	}
+	return w.input
+}
+
+func newInput() *Input {
+	return &Input{
+		down:     make(map[Trigger]bool),
+		pressed:  make(map[Trigger]bool),
+		released: make(map[Trigger]bool),
+		actions:  make(map[string][]Trigger),
+	}
+}
+
+// install sets the callbacks of the window
+func (in *Input) install(win *glfw.Window) {
+	win.SetKeyCallback(func(_ *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+		in.push(KeyEvent{key, scancode, action, mods})
+		in.setTrigger(KeyTrigger(key), action)
+	})
+	win.SetCharCallback(func(_ *glfw.Window, char rune) {
+		in.push(CharEvent{char})
+	})
+	win.SetMouseButtonCallback(func(_ *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
+		in.push(MouseButtonEvent{button, action, mods})
+		in.setTrigger(ButtonTrigger(button), action)
+	})
+	win.SetCursorPosCallback(func(_ *glfw.Window, x, y float64) {
+		in.push(CursorEvent{x, y})
+		if in.cursorValid {
+			in.deltaX += x - in.cursorX
+			in.deltaY += y - in.cursorY
+		}
+		in.cursorX, in.cursorY, in.cursorValid = x, y, true
+	})
+	win.SetScrollCallback(func(_ *glfw.Window, x, y float64) {
+		in.push(ScrollEvent{x, y})
+		in.scrollX += x
+		in.scrollY += y
+	})
+	win.SetSizeCallback(func(_ *glfw.Window, width, height int) {
+		in.push(ResizeEvent{width, height})
+	})
+	win.SetFramebufferSizeCallback(func(_ *glfw.Window, width, height int) {
+		in.push(FramebufferResizeEvent{width, height})
+	})
+	win.SetDropCallback(func(_ *glfw.Window, paths []string) {
+		in.push(DropEvent{paths})
+	})
+	in.cursorX, in.cursorY = win.GetCursorPos()
+	in.cursorValid = true
+}
+
+func (in *Input) push(ev Event) {
+	in.events = append(in.events, ev)
+}
+
+func (in *Input) setTrigger(t Trigger, action glfw.Action) {
+	switch action {
+	case glfw.Press:
+		if !in.down[t] {
+			in.pressed[t] = true
+		}
+		in.down[t] = true
+	case glfw.Release:
+		if in.down[t] {
+			in.released[t] = true
+		}
+		delete(in.down, t)
+	}
+}
+
+// newFrame clears the events and the per-frame state
+func (in *Input) newFrame() {
+	in.events = in.events[:0]
+	for t := range in.pressed {
+		delete(in.pressed, t)
+	}
+	for t := range in.released {
+		delete(in.released, t)
+	}
+	in.deltaX, in.deltaY = 0, 0
+	in.scrollX, in.scrollY = 0, 0
+}
+
+// Events returns the events received in this frame, in order
+// The slice is reused in the next frame
+func (in *Input) Events() []Event {
+	return in.events
+}
+
+// KeyDown reports if the key is held down
+func (in *Input) KeyDown(key glfw.Key) bool { return in.down[KeyTrigger(key)] }
+
+// KeyPressed reports if the key went down in this frame
+func (in *Input) KeyPressed(key glfw.Key) bool { return in.pressed[KeyTrigger(key)] }
+
+// KeyReleased reports if the key went up in this frame
+func (in *Input) KeyReleased(key glfw.Key) bool { return in.released[KeyTrigger(key)] }
+
+// ButtonDown reports if the mouse button is held down
+func (in *Input) ButtonDown(b glfw.MouseButton) bool { return in.down[ButtonTrigger(b)] }
+
+// ButtonPressed reports if the mouse button went down in this frame
+func (in *Input) ButtonPressed(b glfw.MouseButton) bool { return in.pressed[ButtonTrigger(b)] }
+
+// ButtonReleased reports if the mouse button went up in this frame
+func (in *Input) ButtonReleased(b glfw.MouseButton) bool { return in.released[ButtonTrigger(b)] }
+
+// Cursor returns the position of the cursor in screen coordinates
+func (in *Input) Cursor() (x, y float64) { return in.cursorX, in.cursorY }
+
+// MouseDelta returns how much the cursor moved in this frame
+func (in *Input) MouseDelta() (dx, dy float64) { return in.deltaX, in.deltaY }
+
+// ScrollDelta returns how much the wheel scrolled in this frame
+func (in *Input) ScrollDelta() (x, y float64) { return in.scrollX, in.scrollY }
+
+// Bind sets the triggers of an action, replacing the previous ones
+// Without triggers the action is removed
+func (in *Input) Bind(action string, triggers ...Trigger) {
+	if len(triggers) == 0 {
+		delete(in.actions, action)
+		return
+	}
+	in.actions[action] = append([]Trigger(nil), triggers...)
+}
+
+// Bindings returns the triggers of an action
+func (in *Input) Bindings(action string) []Trigger {
+	return in.actions[action]
+}
+
+// anyTrigger reports if any trigger of the action is in the set
+func (in *Input) anyTrigger(action string, set map[Trigger]bool) bool {
+	for _, t := range in.actions[action] {
+		if set[t] {
+			return true
+		}
+	}
+	return false
+}
+
+// Action reports if any trigger of the action is held down
+func (in *Input) Action(action string) bool { return in.anyTrigger(action, in.down) }
+
+// ActionPressed reports if any trigger of the action went down in this frame
+func (in *Input) ActionPressed(action string) bool { return in.anyTrigger(action, in.pressed) }
+
+// ActionReleased reports if any trigger of the action went up in this frame
+func (in *Input) ActionReleased(action string) bool { return in.anyTrigger(action, in.released) }
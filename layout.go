@@ -0,0 +1,303 @@
+package glad
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unsafe"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+)
+
+// VertexLayout describes how the attributes of a vertex are laid out in memory
+// A layout is usually derived from a Go struct with LayoutOf, where each
+// field is an attribute. Fields can be scalars or arrays of 1 to 4 elements
+// of float32, float64, Half, int8, uint8, int16, uint16, int32 or uint32,
+// or a single PackedNormal or PackedFloat3.
+// The `glad` tag specifies the name of the attribute in the shader and some
+// options, separated by commas:
+//
+//	type Vertex struct {
+//		Pos   [3]float32 `glad:"pos"`
+//		Color [4]uint8   `glad:"col,normalized"`
+//		Bones [4]uint8   `glad:"bones,int"`
+//		Pad   float32    `glad:"-"` // Not an attribute, but takes space
+//	}
+//
+// Options are "normalized" to normalize integer data, "int" to pass integers
+// to the shader without conversion to float (e.g. for ivec4 attributes) and
+// "double" to pass float64 data as double (e.g. for dvec3 attributes).
+// Exported fields without tag use the field name, unexported fields are ignored
+type VertexLayout struct {
+	Stride  int32        // Size in bytes of a vertex
+	Attribs []AttribSpec // Attributes in the vertex
+}
+
+// AttribSpec describes the format of an attribute in a vertex
+type AttribSpec struct {
+	Name       string // Name of the attribute in the shader
+	Size       int32  // Number of components, 1 to 4
+	Type       uint32 // Type of each component, e.g. gl.FLOAT or gl.UNSIGNED_BYTE
+	Normalized bool   // Integer data is normalized to [0, 1] or [-1, 1]
+	Integer    bool   // Integer data is not converted to float (see AttribIFormat)
+	Double     bool   // Double data is not converted to float (see AttribLFormat)
+	Offset     uint32 // Offset in bytes from the start of the vertex
+}
+
+// glTypes maps Go kinds to the GL type of vertex components
+var glTypes = map[reflect.Kind]uint32{
+	reflect.Float32: gl.FLOAT,
+	reflect.Float64: gl.DOUBLE,
+	reflect.Int8:    gl.BYTE,
+	reflect.Uint8:   gl.UNSIGNED_BYTE,
+	reflect.Int16:   gl.SHORT,
+	reflect.Uint16:  gl.UNSIGNED_SHORT,
+	reflect.Int32:   gl.INT,
+	reflect.Uint32:  gl.UNSIGNED_INT,
+}
+
+// packedTypes maps the packed types to their GL type and number of components
+var packedTypes = map[reflect.Type][2]uint32{
+	reflect.TypeOf(Half(0)):         {gl.HALF_FLOAT, 1},
+	reflect.TypeOf(PackedNormal(0)): {gl.INT_2_10_10_10_REV, 4},
+	reflect.TypeOf(PackedFloat3(0)): {gl.UNSIGNED_INT_10F_11F_11F_REV, 3},
+}
+
+// LayoutOf derives the layout of a vertex from its struct type
+// vertex can be a struct value, a pointer to struct or a slice of structs
+// It makes no GL calls, so it can be used from any goroutine
+func LayoutOf(vertex interface{}) (*VertexLayout, error) {
+	t := reflect.TypeOf(vertex)
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("vertex layout needs a struct, got %T", vertex)
+	}
+
+	layout := VertexLayout{Stride: int32(t.Size())}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue // Unexported
+		}
+		tag := strings.Split(f.Tag.Get("glad"), ",")
+		if tag[0] == "-" {
+			continue
+		}
+		at := AttribSpec{Name: tag[0], Offset: uint32(f.Offset), Size: 1}
+		if at.Name == "" {
+			at.Name = f.Name
+		}
+		for _, opt := range tag[1:] {
+			switch opt {
+			case "normalized":
+				at.Normalized = true
+			case "int":
+				at.Integer = true
+			case "double":
+				at.Double = true
+			default:
+				return nil, fmt.Errorf("field %s: unknown option %q", f.Name, opt)
+			}
+		}
+		ft := f.Type
+		if ft.Kind() == reflect.Array {
+			if ft.Len() < 1 || ft.Len() > 4 {
+				return nil, fmt.Errorf("field %s: arrays must have 1 to 4 elements", f.Name)
+			}
+			at.Size = int32(ft.Len())
+			ft = ft.Elem()
+		}
+		pt, packed := packedTypes[ft]
+		if packed {
+			if pt[1] > 1 {
+				if at.Size > 1 {
+					return nil, fmt.Errorf("field %s: arrays of %s are not supported", f.Name, ft)
+				}
+				at.Size = int32(pt[1])
+				// Packed normals are meaningful only when normalized
+				at.Normalized = at.Normalized || pt[0] == gl.INT_2_10_10_10_REV
+			}
+			at.Type = pt[0]
+		} else if typ, ok := glTypes[ft.Kind()]; ok {
+			at.Type = typ
+		} else {
+			return nil, fmt.Errorf("field %s: unsupported type %s", f.Name, f.Type)
+		}
+		if at.Integer && (packed || at.Type == gl.FLOAT || at.Type == gl.DOUBLE) {
+			return nil, fmt.Errorf("field %s: option int requires integer data", f.Name)
+		}
+		if at.Double && at.Type != gl.DOUBLE {
+			return nil, fmt.Errorf("field %s: option double requires float64 data", f.Name)
+		}
+		layout.Attribs = append(layout.Attribs, at)
+	}
+	if len(layout.Attribs) == 0 {
+		return nil, fmt.Errorf("vertex %s has no attributes", t)
+	}
+	return &layout, nil
+}
+
+// BufferDataStructs allocates a new data store in the VBO and copies the
+// vertices in it. vertices must be a slice, usually of structs (e.g. []MyVertex)
+// See BufferData32 for the meaning of usage
+func (vbo VertexBufferObject) BufferDataStructs(vertices interface{}, usage uint32) {
+	checkThread()
+	ptr, size := sliceData(vertices)
+	gl.NamedBufferData(uint32(vbo), size, ptr, usage)
+	registry.resize("VertexBufferObject", uint32(vbo), size)
+}
+
+// BufferSubDataStructs replaces part of the buffer content with the vertices
+// starting at offset bytes. vertices must be a slice, usually of structs
+func (vbo VertexBufferObject) BufferSubDataStructs(vertices interface{}, offset int) {
+	checkThread()
+	ptr, size := sliceData(vertices)
+	gl.NamedBufferSubData(uint32(vbo), offset, size, ptr)
+}
+
+// sliceData returns the pointer to the first element of a slice and its
+// size in bytes, or nil if the slice is empty
+func sliceData(slice interface{}) (unsafe.Pointer, int) {
+	v := reflect.ValueOf(slice)
+	if v.Kind() != reflect.Slice {
+		panic(fmt.Sprintf("a slice is required, got %T", slice))
+	}
+	if v.Len() == 0 {
+		return nil, 0
+	}
+	return unsafe.Pointer(v.Pointer()), v.Len() * int(v.Type().Elem().Size())
+}
+
+// SetLayout configures the VAO to read the vertices of the layout from the
+// buffer, matching the attributes by name with the ones of the program
+// The buffer is bound to bindIndex. Attributes of the layout not used by the
+// program are skipped, since drivers remove unused inputs: the same layout
+// can be used with shaders reading only some of its attributes. An error is
+// returned if the program uses an attribute not in the layout, or if the
+// base type (float, int, uint or double) or the number of components of an
+// attribute do not match
+func (vao VertexArrayObject) SetLayout(pr Program, bindIndex uint32, buffer VertexBufferObject, layout *VertexLayout) error {
+	active := make(map[string]ActiveAttrib)
+	for _, aa := range pr.GetActiveAttributes() {
+		if !strings.HasPrefix(aa.Name, "gl_") {
+			active[aa.Name] = aa
+		}
+	}
+	var used []AttribSpec
+	for _, at := range layout.Attribs {
+		aa, ok := active[at.Name]
+		if !ok {
+			continue
+		}
+		if err := checkAttrib(at, aa); err != nil {
+			return err
+		}
+		delete(active, at.Name)
+		used = append(used, at)
+	}
+	for name := range active {
+		return fmt.Errorf("attribute %q used by the program is missing in the layout", name)
+	}
+
+	vao.VertexBuffer(bindIndex, buffer, 0, layout.Stride)
+	for _, at := range used {
+		loc := pr.GetAttributeLocation(at.Name)
+		vao.attribSpec(bindIndex, loc, at)
+	}
+	return nil
+}
+
+// attribSpec sets the format of the attribute, binds it to bindIndex and enables it
+func (vao VertexArrayObject) attribSpec(bindIndex uint32, loc VertexAttrib, at AttribSpec) {
+	switch {
+	case at.Integer:
+		vao.AttribIFormat(loc, at.Size, at.Type, at.Offset)
+	case at.Double:
+		vao.AttribLFormat(loc, at.Size, at.Type, at.Offset)
+	default:
+		vao.AttribFormat(loc, at.Size, at.Type, at.Normalized, at.Offset)
+	}
+	vao.AttribBinding(bindIndex, loc)
+	vao.EnableAttrib(loc)
+}
+
+// checkAttrib returns an error if the attribute of the layout can't feed the
+// active attribute of the program
+func checkAttrib(at AttribSpec, aa ActiveAttrib) error {
+	base, comps, ok := shaderAttribType(aa.Type)
+	if !ok || aa.Size != 1 {
+		return fmt.Errorf("attribute %q: matrix and array attributes are not supported", at.Name)
+	}
+	if lb := layoutBaseType(at); lb != base {
+		hint := ""
+		switch base {
+		case "int", "uint":
+			hint = " (use the int option with " + base + " data for integer attributes)"
+		case "double":
+			hint = " (use the double option for double attributes)"
+		}
+		return fmt.Errorf("attribute %q: the program expects %s components, the layout has %s%s", at.Name, base, lb, hint)
+	}
+	if comps != at.Size {
+		return fmt.Errorf("attribute %q: the program expects %d components, the layout has %d", at.Name, comps, at.Size)
+	}
+	return nil
+}
+
+// layoutBaseType returns the type of the components of the attribute as
+// seen by the shader: "float", "int", "uint" or "double"
+func layoutBaseType(at AttribSpec) string {
+	switch {
+	case at.Double:
+		return "double"
+	case at.Integer:
+		switch at.Type {
+		case gl.UNSIGNED_BYTE, gl.UNSIGNED_SHORT, gl.UNSIGNED_INT:
+			return "uint"
+		}
+		return "int"
+	}
+	return "float"
+}
+
+// shaderAttribType returns the base type and the number of components of a
+// type of shader attribute, false for matrices and unknown types
+func shaderAttribType(typ uint32) (base string, comps int32, ok bool) {
+	switch typ {
+	case gl.FLOAT:
+		return "float", 1, true
+	case gl.FLOAT_VEC2:
+		return "float", 2, true
+	case gl.FLOAT_VEC3:
+		return "float", 3, true
+	case gl.FLOAT_VEC4:
+		return "float", 4, true
+	case gl.INT:
+		return "int", 1, true
+	case gl.INT_VEC2:
+		return "int", 2, true
+	case gl.INT_VEC3:
+		return "int", 3, true
+	case gl.INT_VEC4:
+		return "int", 4, true
+	case gl.UNSIGNED_INT:
+		return "uint", 1, true
+	case gl.UNSIGNED_INT_VEC2:
+		return "uint", 2, true
+	case gl.UNSIGNED_INT_VEC3:
+		return "uint", 3, true
+	case gl.UNSIGNED_INT_VEC4:
+		return "uint", 4, true
+	case gl.DOUBLE:
+		return "double", 1, true
+	case gl.DOUBLE_VEC2:
+		return "double", 2, true
+	case gl.DOUBLE_VEC3:
+		return "double", 3, true
+	case gl.DOUBLE_VEC4:
+		return "double", 4, true
+	}
+	return "", 0, false
+}
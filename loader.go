@@ -0,0 +1,206 @@
+package glad
+
+import (
+	"image"
+	"sync"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+// Loader uploads resources on a hidden context shared with a window, from a
+// worker thread, so that the render loop is not stalled by big uploads.
+// Each request returns a future, completed when the upload is done; a fence
+// makes sure the data is ready before the main context uses it. Typical usage is
+//
+//	loader, err := glad.NewLoader(win, glad.ContextVersion(4, 5), glad.CoreProfile(true))
+//	tf := loader.TextureFile("rock.png")
+//	for !win.ShouldClose() {
+//		if tf != nil && tf.Ready() {
+//			rock, err = tf.Get()
+//			tf = nil
+//		}
+//		// ... draw ...
+//	}
+//
+// Only objects holding data are shared between contexts: container objects
+// like VAOs and framebuffers must be created on the main context
+type Loader struct {
+	win *Window
+	ctx *Context
+}
+
+// loaderThreads holds the goroutines of the loaders, allowed to call the
+// wrappers when the debug mode of a Context is enabled
+var loaderThreads sync.Map
+
+// NewLoader creates a hidden window with a context shared with main, and a
+// worker thread where the context is current. It must be called on the main
+// thread. The options should request the same context version and profile
+// of main, the context current on the caller is restored before returning
+func NewLoader(main *Window, opts ...WinOption) (*Loader, error) {
+	prev := glfw.GetCurrentContext()
+	all := append([]WinOption{Visible(false)}, opts...)
+	all = append(all, SharedWith(main))
+	win, err := NewWindow(1, 1, "loader", all...)
+	if err != nil {
+		return nil, err
+	}
+	glfw.DetachCurrentContext()
+	if prev != nil {
+		prev.MakeContextCurrent()
+	}
+
+	l := &Loader{win: win, ctx: NewContext()}
+	l.ctx.Do(func() {
+		win.MakeCurrent()
+		loaderThreads.Store(goid(), true)
+	})
+	return l, nil
+}
+
+// Close stops the worker after the pending uploads and destroys the hidden
+// context. It must be called on the main thread. Uploads requested after
+// Close fail with ErrContextStopped
+func (l *Loader) Close() {
+	// The context is detached only after the queue is drained: uploads
+	// submitted concurrently either run before it or fail
+	<-l.ctx.stopAfter(func() {
+		glfw.DetachCurrentContext()
+		loaderThreads.Delete(goid())
+	})
+	l.win.Destroy()
+}
+
+// Future is the result of an upload done by a Loader
+// Its methods must be called on the thread of the main context
+type Future struct {
+	done  chan struct{}
+	err   error
+	fence Fence // Signaled when the GPU completed the upload, 0 after Wait
+}
+
+func newFuture() *Future {
+	return &Future{done: make(chan struct{})}
+}
+
+// finish completes the future on the loader thread
+func (f *Future) finish(err error) {
+	f.err = err
+	if err == nil {
+		f.fence = NewFence()
+		// The fence must reach the GPU before other contexts wait for it
+		gl.Flush()
+	}
+	close(f.done)
+}
+
+// Done returns a channel closed when the upload has been submitted
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Ready reports if the upload is completed and Wait would not block
+func (f *Future) Ready() bool {
+	select {
+	case <-f.done:
+	default:
+		return false
+	}
+	return f.err != nil || f.fence == 0 || f.fence.Signaled()
+}
+
+// Wait blocks until the upload has been submitted, then makes the current
+// context wait for the GPU to complete it, and returns the error of the
+// upload. Objects must be bound again on the current context after this, to
+// see their new contents
+func (f *Future) Wait() error {
+	<-f.done
+	if f.fence != 0 {
+		f.fence.Wait()
+		f.fence.Delete()
+		f.fence = 0
+	}
+	return f.err
+}
+
+// submit runs upload on the loader thread, completing f when it returns
+// If the loader is closed f completes with ErrContextStopped
+func (l *Loader) submit(f *Future, upload func()) {
+	errc := l.ctx.DoAsync(func() {
+		f.finish(protect(upload))
+	})
+	// A stopped context reports the error before DoAsync returns
+	select {
+	case err := <-errc:
+		if err == ErrContextStopped {
+			f.finish(err) // upload did not run, no GL calls without a context
+		}
+	default:
+	}
+}
+
+// Do runs fn on the loader context, e.g. to fill an object created by the
+// caller. The future completes after fn returns
+func (l *Loader) Do(fn func()) *Future {
+	f := newFuture()
+	l.submit(f, fn)
+	return f
+}
+
+// TextureFuture is the result of a texture upload
+type TextureFuture struct {
+	Future
+	tex Texture
+}
+
+// Get waits for the upload and returns the texture
+func (f *TextureFuture) Get() (Texture, error) {
+	err := f.Wait()
+	return f.tex, err
+}
+
+// Texture uploads the image into a new texture, see NewTextureFromImage
+func (l *Loader) Texture(img image.Image) *TextureFuture {
+	f := &TextureFuture{Future: *newFuture()}
+	l.submit(&f.Future, func() { f.tex = NewTextureFromImage(img) })
+	return f
+}
+
+// TextureFile reads an image file (PNG or JPEG) into a new texture
+// The image is decoded on its own goroutine, without blocking the uploads
+func (l *Loader) TextureFile(path string) *TextureFuture {
+	f := &TextureFuture{Future: *newFuture()}
+	go func() {
+		img, err := decodeImage(path)
+		if err != nil {
+			f.finish(err) // No GL calls without a fence
+			return
+		}
+		l.submit(&f.Future, func() { f.tex = NewTextureFromImage(img) })
+	}()
+	return f
+}
+
+// BufferFuture is the result of a buffer upload
+type BufferFuture struct {
+	Future
+	vbo VertexBufferObject
+}
+
+// Get waits for the upload and returns the buffer
+func (f *BufferFuture) Get() (VertexBufferObject, error) {
+	err := f.Wait()
+	return f.vbo, err
+}
+
+// Buffer32 uploads the data into a new buffer with immutable storage, see
+// BufferStorage32. data must not be modified until the future is done
+func (l *Loader) Buffer32(data []float32, flags uint32) *BufferFuture {
+	f := &BufferFuture{Future: *newFuture()}
+	l.submit(&f.Future, func() {
+		f.vbo = NewVertexBufferObject()
+		f.vbo.BufferStorage32(data, flags)
+	})
+	return f
+}
@@ -0,0 +1,209 @@
+package mathx
+
+// Mat3 is a 3x3 matrix in column-major order: element (row r, column c)
+// is at index c*3+r
+type Mat3 [9]float32
+
+// Mat4 is a 4x4 matrix in column-major order: element (row r, column c)
+// is at index c*4+r
+type Mat4 [16]float32
+
+// Ident3 returns the 3x3 identity matrix
+func Ident3() Mat3 {
+	return Mat3{1, 0, 0, 0, 1, 0, 0, 0, 1}
+}
+
+// Ident4 returns the 4x4 identity matrix
+func Ident4() Mat4 {
+	return Mat4{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}
+}
+
+// At returns the element at row r and column c
+func (m Mat3) At(r, c int) float32 { return m[c*3+r] }
+
+// Col returns the column c
+func (m Mat3) Col(c int) Vec3 { return Vec3{m[c*3], m[c*3+1], m[c*3+2]} }
+
+// Mul returns the product m * b
+func (m Mat3) Mul(b Mat3) Mat3 {
+	var p Mat3
+	for c := 0; c < 3; c++ {
+		for r := 0; r < 3; r++ {
+			p[c*3+r] = m[r]*b[c*3] + m[3+r]*b[c*3+1] + m[6+r]*b[c*3+2]
+		}
+	}
+	return p
+}
+
+// MulVec returns the product m * v
+func (m Mat3) MulVec(v Vec3) Vec3 {
+	return Vec3{
+		m[0]*v[0] + m[3]*v[1] + m[6]*v[2],
+		m[1]*v[0] + m[4]*v[1] + m[7]*v[2],
+		m[2]*v[0] + m[5]*v[1] + m[8]*v[2],
+	}
+}
+
+// Transpose returns the transpose of m
+func (m Mat3) Transpose() Mat3 {
+	return Mat3{m[0], m[3], m[6], m[1], m[4], m[7], m[2], m[5], m[8]}
+}
+
+// Det returns the determinant of m
+func (m Mat3) Det() float32 {
+	return m[0]*(m[4]*m[8]-m[7]*m[5]) - m[3]*(m[1]*m[8]-m[7]*m[2]) + m[6]*(m[1]*m[5]-m[4]*m[2])
+}
+
+// Inverse returns the inverse of m, ok is false if m is singular
+func (m Mat3) Inverse() (inv Mat3, ok bool) {
+	det := m.Det()
+	if det == 0 {
+		return Mat3{}, false
+	}
+	d := 1 / det
+	return Mat3{
+		(m[4]*m[8] - m[7]*m[5]) * d,
+		(m[7]*m[2] - m[1]*m[8]) * d,
+		(m[1]*m[5] - m[4]*m[2]) * d,
+		(m[6]*m[5] - m[3]*m[8]) * d,
+		(m[0]*m[8] - m[6]*m[2]) * d,
+		(m[3]*m[2] - m[0]*m[5]) * d,
+		(m[3]*m[7] - m[6]*m[4]) * d,
+		(m[6]*m[1] - m[0]*m[7]) * d,
+		(m[0]*m[4] - m[3]*m[1]) * d,
+	}, true
+}
+
+// Mat4 returns m extended to a 4x4 matrix
+func (m Mat3) Mat4() Mat4 {
+	return Mat4{m[0], m[1], m[2], 0, m[3], m[4], m[5], 0, m[6], m[7], m[8], 0, 0, 0, 0, 1}
+}
+
+// At returns the element at row r and column c
+func (m Mat4) At(r, c int) float32 { return m[c*4+r] }
+
+// Col returns the column c
+func (m Mat4) Col(c int) Vec4 { return Vec4{m[c*4], m[c*4+1], m[c*4+2], m[c*4+3]} }
+
+// Mul returns the product m * b, which applies b first and then m
+func (m Mat4) Mul(b Mat4) Mat4 {
+	var p Mat4
+	for c := 0; c < 4; c++ {
+		for r := 0; r < 4; r++ {
+			p[c*4+r] = m[r]*b[c*4] + m[4+r]*b[c*4+1] + m[8+r]*b[c*4+2] + m[12+r]*b[c*4+3]
+		}
+	}
+	return p
+}
+
+// MulVec returns the product m * v
+func (m Mat4) MulVec(v Vec4) Vec4 {
+	var p Vec4
+	for r := 0; r < 4; r++ {
+		p[r] = m[r]*v[0] + m[4+r]*v[1] + m[8+r]*v[2] + m[12+r]*v[3]
+	}
+	return p
+}
+
+// MulPoint transforms the point p, dividing by w if the matrix is a projection
+func (m Mat4) MulPoint(p Vec3) Vec3 {
+	v := m.MulVec(p.Vec4(1))
+	if v[3] != 1 && v[3] != 0 {
+		return v.Project()
+	}
+	return v.Vec3()
+}
+
+// MulDir transforms the direction d, ignoring the translation
+func (m Mat4) MulDir(d Vec3) Vec3 {
+	return m.MulVec(d.Vec4(0)).Vec3()
+}
+
+// Transpose returns the transpose of m
+func (m Mat4) Transpose() Mat4 {
+	var t Mat4
+	for c := 0; c < 4; c++ {
+		for r := 0; r < 4; r++ {
+			t[r*4+c] = m[c*4+r]
+		}
+	}
+	return t
+}
+
+// Mat3 returns the upper-left 3x3 part of m
+func (m Mat4) Mat3() Mat3 {
+	return Mat3{m[0], m[1], m[2], m[4], m[5], m[6], m[8], m[9], m[10]}
+}
+
+// cofactors returns the 2x2 determinants used by Det and Inverse
+func (m Mat4) cofactors() (s, c [6]float32) {
+	s = [6]float32{
+		m[0]*m[5] - m[4]*m[1],
+		m[0]*m[9] - m[8]*m[1],
+		m[0]*m[13] - m[12]*m[1],
+		m[4]*m[9] - m[8]*m[5],
+		m[4]*m[13] - m[12]*m[5],
+		m[8]*m[13] - m[12]*m[9],
+	}
+	c = [6]float32{
+		m[2]*m[7] - m[6]*m[3],
+		m[2]*m[11] - m[10]*m[3],
+		m[2]*m[15] - m[14]*m[3],
+		m[6]*m[11] - m[10]*m[7],
+		m[6]*m[15] - m[14]*m[7],
+		m[10]*m[15] - m[14]*m[11],
+	}
+	return
+}
+
+// Det returns the determinant of m
+func (m Mat4) Det() float32 {
+	s, c := m.cofactors()
+	return s[0]*c[5] - s[1]*c[4] + s[2]*c[3] + s[3]*c[2] - s[4]*c[1] + s[5]*c[0]
+}
+
+// Inverse returns the inverse of m, ok is false if m is singular
+func (m Mat4) Inverse() (inv Mat4, ok bool) {
+	s, c := m.cofactors()
+	det := s[0]*c[5] - s[1]*c[4] + s[2]*c[3] + s[3]*c[2] - s[4]*c[1] + s[5]*c[0]
+	if det == 0 {
+		return Mat4{}, false
+	}
+	d := 1 / det
+	// a returns the element at row r and column col
+	a := func(r, col int) float32 { return m[col*4+r] }
+	inv = Mat4{
+		(a(1, 1)*c[5] - a(1, 2)*c[4] + a(1, 3)*c[3]) * d,
+		(-a(1, 0)*c[5] + a(1, 2)*c[2] - a(1, 3)*c[1]) * d,
+		(a(1, 0)*c[4] - a(1, 1)*c[2] + a(1, 3)*c[0]) * d,
+		(-a(1, 0)*c[3] + a(1, 1)*c[1] - a(1, 2)*c[0]) * d,
+
+		(-a(0, 1)*c[5] + a(0, 2)*c[4] - a(0, 3)*c[3]) * d,
+		(a(0, 0)*c[5] - a(0, 2)*c[2] + a(0, 3)*c[1]) * d,
+		(-a(0, 0)*c[4] + a(0, 1)*c[2] - a(0, 3)*c[0]) * d,
+		(a(0, 0)*c[3] - a(0, 1)*c[1] + a(0, 2)*c[0]) * d,
+
+		(a(3, 1)*s[5] - a(3, 2)*s[4] + a(3, 3)*s[3]) * d,
+		(-a(3, 0)*s[5] + a(3, 2)*s[2] - a(3, 3)*s[1]) * d,
+		(a(3, 0)*s[4] - a(3, 1)*s[2] + a(3, 3)*s[0]) * d,
+		(-a(3, 0)*s[3] + a(3, 1)*s[1] - a(3, 2)*s[0]) * d,
+
+		(-a(2, 1)*s[5] + a(2, 2)*s[4] - a(2, 3)*s[3]) * d,
+		(a(2, 0)*s[5] - a(2, 2)*s[2] + a(2, 3)*s[1]) * d,
+		(-a(2, 0)*s[4] + a(2, 1)*s[2] - a(2, 3)*s[0]) * d,
+		(a(2, 0)*s[3] - a(2, 1)*s[1] + a(2, 2)*s[0]) * d,
+	}
+	return inv, true
+}
+
+// NormalMatrix returns the matrix to transform normals with the model-view
+// matrix m: the inverse transpose of its upper-left 3x3 part. If the part is
+// singular it is returned as it is
+func (m Mat4) NormalMatrix() Mat3 {
+	m3 := m.Mat3()
+	inv, ok := m3.Inverse()
+	if !ok {
+		return m3
+	}
+	return inv.Transpose()
+}
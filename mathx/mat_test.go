@@ -0,0 +1,110 @@
+package mathx
+
+import (
+	"math"
+	"testing"
+)
+
+const eps = 1e-5
+
+func near(a, b float32) bool { return math.Abs(float64(a-b)) < eps }
+
+func nearMat4(a, b Mat4) bool {
+	for i := range a {
+		if !near(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func nearVec3(a, b Vec3) bool {
+	return near(a[0], b[0]) && near(a[1], b[1]) && near(a[2], b[2])
+}
+
+func TestMat4Inverse(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Mat4
+	}{
+		{"Identity", Ident4()},
+		{"Translate", Translate(Vec3{1, -2, 3})},
+		{"TRS", TRS(Vec3{4, 5, 6}, QuatAxisAngle(Vec3{1, 2, 3}, 0.7), Vec3{2, 0.5, 3})},
+		{"Perspective", Perspective(Radians(60), 1.5, 0.1, 100)},
+		{"Ortho", Ortho(-2, 3, -1, 4, 0.5, 20)},
+		{"LookAt", LookAt(Vec3{1, 2, 3}, Vec3{-1, 0, 2}, Vec3{0, 1, 0})},
+		{"Dense", Mat4{2, 1, 0, 3, 1, 4, 2, 0, 0, 1, 5, 1, 3, 0, 1, 6}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inv, ok := tt.m.Inverse()
+			if !ok {
+				t.Fatal("matrix reported singular")
+			}
+			if p := inv.Mul(tt.m); !nearMat4(p, Ident4()) {
+				t.Errorf("Inverse * M = %v, want identity", p)
+			}
+			if p := tt.m.Mul(inv); !nearMat4(p, Ident4()) {
+				t.Errorf("M * Inverse = %v, want identity", p)
+			}
+			if d := tt.m.Det() * inv.Det(); !near(d, 1) {
+				t.Errorf("det(M) * det(Inverse) = %v, want 1", d)
+			}
+		})
+	}
+}
+
+func TestSingular(t *testing.T) {
+	if _, ok := Scale(Vec3{1, 0, 1}).Inverse(); ok {
+		t.Error("Mat4 with a zero scale reported invertible")
+	}
+	if _, ok := (Mat4{1, 2, 3, 4, 2, 4, 6, 8, 0, 1, 0, 1, 5, 0, 2, 1}).Inverse(); ok {
+		t.Error("Mat4 with dependent columns reported invertible")
+	}
+	if _, ok := (Mat3{1, 2, 3, 2, 4, 6, 0, 1, 0}).Inverse(); ok {
+		t.Error("Mat3 with dependent columns reported invertible")
+	}
+	// The normal matrix of a singular transform is its 3x3 part
+	m := Scale(Vec3{2, 0, 3})
+	if n := m.NormalMatrix(); n != m.Mat3() {
+		t.Errorf("NormalMatrix = %v, want %v", n, m.Mat3())
+	}
+}
+
+func TestMat3Inverse(t *testing.T) {
+	m := Mat3{2, 1, 0, 1, 3, 1, 0, 1, 4}
+	inv, ok := m.Inverse()
+	if !ok {
+		t.Fatal("matrix reported singular")
+	}
+	if p := inv.Mul(m).Mat4(); !nearMat4(p, Ident4()) {
+		t.Errorf("Inverse * M = %v, want identity", p)
+	}
+}
+
+func TestNormalMatrix(t *testing.T) {
+	// With a non-uniform scale, normals must stay orthogonal to the surface
+	m := TRS(Vec3{1, 2, 3}, QuatAxisAngle(Vec3{0, 1, 1}, 0.5), Vec3{1, 4, 0.5})
+	tangent, normal := Vec3{1, 1, 0}, Vec3{1, -1, 0}
+	n := m.NormalMatrix().MulVec(normal)
+	if d := m.MulDir(tangent).Dot(n); !near(d, 0) {
+		t.Errorf("transformed normal is not orthogonal to the tangent: dot %v", d)
+	}
+}
+
+func TestMulLayout(t *testing.T) {
+	// Column-major: the translation is in elements 12 to 14
+	m := Translate(Vec3{1, 2, 3}).Mul(Scale(Vec3{2, 2, 2}))
+	if got, want := m.MulPoint(Vec3{1, 1, 1}), (Vec3{3, 4, 5}); got != want {
+		t.Errorf("MulPoint = %v, want %v", got, want)
+	}
+	if got, want := m.MulDir(Vec3{1, 1, 1}), (Vec3{2, 2, 2}); got != want {
+		t.Errorf("MulDir = %v, want %v", got, want)
+	}
+	if m.At(0, 3) != 1 || m.Col(3) != (Vec4{1, 2, 3, 1}) {
+		t.Errorf("At/Col do not match the column-major layout: %v", m)
+	}
+	if m.Transpose().At(3, 0) != 1 {
+		t.Errorf("Transpose = %v", m.Transpose())
+	}
+}
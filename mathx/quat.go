@@ -0,0 +1,160 @@
+package mathx
+
+import "math"
+
+// Quat is a quaternion X*i + Y*j + Z*k + W, stored in this order as in glTF
+// Unit quaternions represent rotations
+type Quat [4]float32
+
+// QuatIdent returns the quaternion of no rotation
+func QuatIdent() Quat { return Quat{0, 0, 0, 1} }
+
+// QuatAxisAngle returns the rotation of angle around axis
+func QuatAxisAngle(axis Vec3, angle float32) Quat {
+	s, c := math.Sincos(float64(angle) / 2)
+	a := axis.Normalize().Mul(float32(s))
+	return Quat{a[0], a[1], a[2], float32(c)}
+}
+
+// QuatEuler returns the rotation that applies roll around Z, then pitch
+// around X, then yaw around Y, the usual order for cameras and characters
+func QuatEuler(pitch, yaw, roll float32) Quat {
+	return QuatAxisAngle(Vec3{0, 1, 0}, yaw).
+		Mul(QuatAxisAngle(Vec3{1, 0, 0}, pitch)).
+		Mul(QuatAxisAngle(Vec3{0, 0, 1}, roll))
+}
+
+// QuatFromMat3 returns the rotation of a rotation matrix (orthonormal, with
+// determinant 1)
+func QuatFromMat3(m Mat3) Quat {
+	var x, y, z, w float32
+	switch tr := m[0] + m[4] + m[8]; {
+	case tr > 0:
+		s := 0.5 / sqrt(tr+1)
+		w, x, y, z = 0.25/s, (m[5]-m[7])*s, (m[6]-m[2])*s, (m[1]-m[3])*s
+	case m[0] > m[4] && m[0] > m[8]:
+		s := 2 * sqrt(1+m[0]-m[4]-m[8])
+		w, x, y, z = (m[5]-m[7])/s, 0.25*s, (m[3]+m[1])/s, (m[6]+m[2])/s
+	case m[4] > m[8]:
+		s := 2 * sqrt(1+m[4]-m[0]-m[8])
+		w, x, y, z = (m[6]-m[2])/s, (m[3]+m[1])/s, 0.25*s, (m[7]+m[5])/s
+	default:
+		s := 2 * sqrt(1+m[8]-m[0]-m[4])
+		w, x, y, z = (m[1]-m[3])/s, (m[6]+m[2])/s, (m[7]+m[5])/s, 0.25*s
+	}
+	return Quat{x, y, z, w}
+}
+
+// Mul returns the product q * r, the rotation that applies r first and then q
+func (q Quat) Mul(r Quat) Quat {
+	return Quat{
+		q[3]*r[0] + q[0]*r[3] + q[1]*r[2] - q[2]*r[1],
+		q[3]*r[1] - q[0]*r[2] + q[1]*r[3] + q[2]*r[0],
+		q[3]*r[2] + q[0]*r[1] - q[1]*r[0] + q[2]*r[3],
+		q[3]*r[3] - q[0]*r[0] - q[1]*r[1] - q[2]*r[2],
+	}
+}
+
+func (q Quat) Dot(r Quat) float32 { return q[0]*r[0] + q[1]*r[1] + q[2]*r[2] + q[3]*r[3] }
+func (q Quat) Len() float32       { return sqrt(q.Dot(q)) }
+
+// Conjugate returns the conjugate of q, the inverse rotation for unit quaternions
+func (q Quat) Conjugate() Quat { return Quat{-q[0], -q[1], -q[2], q[3]} }
+
+// Inverse returns the inverse of q
+func (q Quat) Inverse() Quat {
+	d := q.Dot(q)
+	if d == 0 {
+		return q
+	}
+	c := q.Conjugate()
+	return Quat{c[0] / d, c[1] / d, c[2] / d, c[3] / d}
+}
+
+// Normalize returns q scaled to unit length
+func (q Quat) Normalize() Quat {
+	l := q.Len()
+	if l == 0 {
+		return QuatIdent()
+	}
+	return Quat{q[0] / l, q[1] / l, q[2] / l, q[3] / l}
+}
+
+// Rotate returns v rotated by the unit quaternion q
+func (q Quat) Rotate(v Vec3) Vec3 {
+	u := Vec3{q[0], q[1], q[2]}
+	t := u.Cross(v).Mul(2)
+	return v.Add(t.Mul(q[3])).Add(u.Cross(t))
+}
+
+// AxisAngle returns axis and angle of the rotation of the unit quaternion q
+// The axis is X for the identity
+func (q Quat) AxisAngle() (axis Vec3, angle float32) {
+	if q[3] < 0 {
+		q = Quat{-q[0], -q[1], -q[2], -q[3]}
+	}
+	s := sqrt(1 - q[3]*q[3])
+	angle = 2 * float32(math.Acos(math.Min(1, float64(q[3]))))
+	if s < 1e-6 {
+		return Vec3{1, 0, 0}, angle
+	}
+	return Vec3{q[0] / s, q[1] / s, q[2] / s}, angle
+}
+
+// Euler returns the angles such that QuatEuler(pitch, yaw, roll) is q
+// At pitch of +/- 90 degrees (gimbal lock) roll is 0
+func (q Quat) Euler() (pitch, yaw, roll float32) {
+	m := q.Mat3()
+	// m = Ry(yaw) * Rx(pitch) * Rz(roll), element (1, 2) is -sin(pitch)
+	sp := -m.At(1, 2)
+	if sp > 1 {
+		sp = 1
+	} else if sp < -1 {
+		sp = -1
+	}
+	pitch = float32(math.Asin(float64(sp)))
+	if math.Abs(float64(sp)) > 0.99999 {
+		yaw = float32(math.Atan2(float64(-m.At(2, 0)), float64(m.At(0, 0))))
+		return pitch, yaw, 0
+	}
+	yaw = float32(math.Atan2(float64(m.At(0, 2)), float64(m.At(2, 2))))
+	roll = float32(math.Atan2(float64(m.At(1, 0)), float64(m.At(1, 1))))
+	return
+}
+
+// Mat3 returns the rotation matrix of the unit quaternion q
+func (q Quat) Mat3() Mat3 {
+	x, y, z, w := q[0], q[1], q[2], q[3]
+	return Mat3{
+		1 - 2*(y*y+z*z), 2 * (x*y + z*w), 2 * (x*z - y*w),
+		2 * (x*y - z*w), 1 - 2*(x*x+z*z), 2 * (y*z + x*w),
+		2 * (x*z + y*w), 2 * (y*z - x*w), 1 - 2*(x*x+y*y),
+	}
+}
+
+// Mat4 returns the rotation matrix of the unit quaternion q
+func (q Quat) Mat4() Mat4 { return q.Mat3().Mat4() }
+
+// Slerp interpolates along the shortest arc between unit quaternions a and b
+// t is 0 for a and 1 for b
+func Slerp(a, b Quat, t float32) Quat {
+	d := a.Dot(b)
+	if d < 0 {
+		b, d = Quat{-b[0], -b[1], -b[2], -b[3]}, -d
+	}
+	if d > 0.9995 {
+		// Almost parallel: linear interpolation is accurate and stable
+		return Quat{
+			a[0] + (b[0]-a[0])*t, a[1] + (b[1]-a[1])*t,
+			a[2] + (b[2]-a[2])*t, a[3] + (b[3]-a[3])*t,
+		}.Normalize()
+	}
+	theta := math.Acos(float64(d))
+	sin := math.Sin(theta)
+	ka := float32(math.Sin((1-float64(t))*theta) / sin)
+	kb := float32(math.Sin(float64(t)*theta) / sin)
+	return Quat{
+		a[0]*ka + b[0]*kb, a[1]*ka + b[1]*kb,
+		a[2]*ka + b[2]*kb, a[3]*ka + b[3]*kb,
+	}
+}
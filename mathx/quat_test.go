@@ -0,0 +1,119 @@
+package mathx
+
+import (
+	"math"
+	"testing"
+)
+
+// sameRotation reports if the unit quaternions are the same rotation
+func sameRotation(a, b Quat) bool {
+	return near(float32(math.Abs(float64(a.Dot(b)))), 1)
+}
+
+func TestQuatAxisAngle(t *testing.T) {
+	q := QuatAxisAngle(Vec3{0, 0, 2}, math.Pi/2)
+	if got := q.Rotate(Vec3{1, 0, 0}); !nearVec3(got, Vec3{0, 1, 0}) {
+		t.Errorf("rotating X by 90 degrees around Z = %v, want Y", got)
+	}
+	if got := q.Mat3().MulVec(Vec3{1, 0, 0}); !nearVec3(got, Vec3{0, 1, 0}) {
+		t.Errorf("Mat3 rotates X to %v, want Y", got)
+	}
+	axis, angle := q.AxisAngle()
+	if !nearVec3(axis, Vec3{0, 0, 1}) || !near(angle, math.Pi/2) {
+		t.Errorf("AxisAngle = %v %v, want Z and pi/2", axis, angle)
+	}
+	if p := q.Mul(q.Inverse()); !sameRotation(p, QuatIdent()) {
+		t.Errorf("q * Inverse = %v, want identity", p)
+	}
+	// Mul applies the right operand first
+	r := QuatAxisAngle(Vec3{1, 0, 0}, math.Pi/2)
+	if got := q.Mul(r).Rotate(Vec3{0, 1, 0}); !nearVec3(got, q.Rotate(r.Rotate(Vec3{0, 1, 0}))) {
+		t.Errorf("(q * r) v = %v, want q (r v)", got)
+	}
+}
+
+func TestSlerp(t *testing.T) {
+	a := QuatAxisAngle(Vec3{0, 1, 0}, 0.2)
+	b := QuatAxisAngle(Vec3{0, 1, 0}, 2.2)
+	if q := Slerp(a, b, 0); !sameRotation(q, a) {
+		t.Errorf("Slerp at 0 = %v, want %v", q, a)
+	}
+	if q := Slerp(a, b, 1); !sameRotation(q, b) {
+		t.Errorf("Slerp at 1 = %v, want %v", q, b)
+	}
+	if q, want := Slerp(a, b, 0.5), QuatAxisAngle(Vec3{0, 1, 0}, 1.2); !sameRotation(q, want) {
+		t.Errorf("Slerp at 0.5 = %v, want %v", q, want)
+	}
+	// The shortest arc is taken even when the quaternions are in opposite
+	// hemispheres
+	nb := Quat{-b[0], -b[1], -b[2], -b[3]}
+	if q, want := Slerp(a, nb, 0.5), QuatAxisAngle(Vec3{0, 1, 0}, 1.2); !sameRotation(q, want) {
+		t.Errorf("Slerp to -b at 0.5 = %v, want %v", q, want)
+	}
+	// Almost parallel quaternions use the linear path
+	c := QuatAxisAngle(Vec3{0, 1, 0}, 0.2001)
+	if q := Slerp(a, c, 0.5); !near(q.Len(), 1) {
+		t.Errorf("Slerp of close quaternions = %v, not unit", q)
+	}
+}
+
+func TestEuler(t *testing.T) {
+	tests := []struct{ pitch, yaw, roll float32 }{
+		{0, 0, 0},
+		{0.3, 0, 0},
+		{0, -1.2, 0},
+		{0, 0, 2.5},
+		{0.4, 1.1, -0.7},
+		{-1.2, 3, 1.5},
+		{1.5, -2.9, -3},
+	}
+	for _, tt := range tests {
+		p, y, r := QuatEuler(tt.pitch, tt.yaw, tt.roll).Euler()
+		if !near(p, tt.pitch) || !near(y, tt.yaw) || !near(r, tt.roll) {
+			t.Errorf("Euler of QuatEuler(%v, %v, %v) = %v, %v, %v", tt.pitch, tt.yaw, tt.roll, p, y, r)
+		}
+	}
+	// At gimbal lock the angles differ, but the rotation must be the same
+	// The pitch is computed with asin, which is not precise close to 1
+	q := QuatEuler(math.Pi/2, 0.5, 0.3)
+	p, y, r := q.Euler()
+	if math.Abs(float64(p)-math.Pi/2) > 1e-3 || r != 0 || !sameRotation(QuatEuler(p, y, r), q) {
+		t.Errorf("Euler at gimbal lock = %v, %v, %v, not the same rotation", p, y, r)
+	}
+}
+
+func TestQuatFromMat3(t *testing.T) {
+	// One case for each branch: positive trace or largest diagonal element
+	tests := []struct {
+		name   string
+		q      Quat
+		branch int // -1 for positive trace, else index of the largest diagonal element
+	}{
+		{"PositiveTrace", QuatAxisAngle(Vec3{1, 2, 3}, 0.8), -1},
+		{"X", QuatAxisAngle(Vec3{1, 0.1, 0.1}, 3), 0},
+		{"Y", QuatAxisAngle(Vec3{0.1, 1, 0.1}, 3), 1},
+		{"Z", QuatAxisAngle(Vec3{0.1, 0.1, 1}, 3), 2},
+		{"HalfTurnX", QuatAxisAngle(Vec3{1, 0, 0}, math.Pi), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.q.Mat3()
+			branch := 0
+			switch {
+			case m[0]+m[4]+m[8] > 0:
+				branch = -1
+			case m[0] > m[4] && m[0] > m[8]:
+			case m[4] > m[8]:
+				branch = 1
+			default:
+				branch = 2
+			}
+			if branch != tt.branch {
+				t.Fatalf("matrix %v selects branch %d, want %d", m, branch, tt.branch)
+			}
+			if q := QuatFromMat3(m); !sameRotation(q, tt.q) {
+				t.Errorf("QuatFromMat3 = %v, want %v", q, tt.q)
+			}
+		})
+	}
+}
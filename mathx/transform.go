@@ -0,0 +1,84 @@
+package mathx
+
+import "math"
+
+// Translate returns the matrix translating by t
+func Translate(t Vec3) Mat4 {
+	return Mat4{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, t[0], t[1], t[2], 1}
+}
+
+// Scale returns the matrix scaling by s on each axis
+func Scale(s Vec3) Mat4 {
+	return Mat4{s[0], 0, 0, 0, 0, s[1], 0, 0, 0, 0, s[2], 0, 0, 0, 0, 1}
+}
+
+// Rotate returns the matrix rotating by angle around axis
+func Rotate(axis Vec3, angle float32) Mat4 {
+	return QuatAxisAngle(axis, angle).Mat4()
+}
+
+// TRS returns the matrix applying scale, then rotation, then translation
+func TRS(t Vec3, r Quat, s Vec3) Mat4 {
+	m := r.Mat4()
+	for c := 0; c < 3; c++ {
+		for i := 0; i < 3; i++ {
+			m[c*4+i] *= s[c]
+		}
+	}
+	m[12], m[13], m[14] = t[0], t[1], t[2]
+	return m
+}
+
+// Perspective returns a perspective projection with vertical field of view
+// fovy, aspect ratio width / height and the distances of the clipping planes
+func Perspective(fovy, aspect, near, far float32) Mat4 {
+	f := float32(1 / math.Tan(float64(fovy)/2))
+	return Mat4{
+		f / aspect, 0, 0, 0,
+		0, f, 0, 0,
+		0, 0, (far + near) / (near - far), -1,
+		0, 0, 2 * far * near / (near - far), 0,
+	}
+}
+
+// Ortho returns an orthographic projection of the given box
+func Ortho(left, right, bottom, top, near, far float32) Mat4 {
+	w, h, d := right-left, top-bottom, far-near
+	return Mat4{
+		2 / w, 0, 0, 0,
+		0, 2 / h, 0, 0,
+		0, 0, -2 / d, 0,
+		-(right + left) / w, -(top + bottom) / h, -(far + near) / d, 1,
+	}
+}
+
+// LookAt returns the view matrix of a camera in eye looking at center, with
+// up pointing upward on the screen
+func LookAt(eye, center, up Vec3) Mat4 {
+	f := center.Sub(eye).Normalize()
+	s := f.Cross(up).Normalize()
+	u := s.Cross(f)
+	return Mat4{
+		s[0], u[0], -f[0], 0,
+		s[1], u[1], -f[1], 0,
+		s[2], u[2], -f[2], 0,
+		-s.Dot(eye), -u.Dot(eye), f.Dot(eye), 1,
+	}
+}
+
+// Radians converts degrees to radians
+func Radians(deg float32) float32 { return deg * math.Pi / 180 }
+
+// Degrees converts radians to degrees
+func Degrees(rad float32) float32 { return rad * 180 / math.Pi }
+
+// Clamp returns x limited to [min, max]
+func Clamp(x, min, max float32) float32 {
+	if x < min {
+		return min
+	}
+	if x > max {
+		return max
+	}
+	return x
+}
@@ -0,0 +1,89 @@
+package mathx
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLookAt(t *testing.T) {
+	// A camera on +Z looking at the origin is a translation
+	if m := LookAt(Vec3{0, 0, 5}, Vec3{}, Vec3{0, 1, 0}); !nearMat4(m, Translate(Vec3{0, 0, -5})) {
+		t.Errorf("LookAt = %v, want a translation by -5 on Z", m)
+	}
+	// A camera on +X looking at the origin: world -X is forward (-Z), world
+	// -Z is on the right (+X)
+	m := LookAt(Vec3{3, 0, 0}, Vec3{}, Vec3{0, 1, 0})
+	tests := []struct{ in, want Vec3 }{
+		{Vec3{3, 0, 0}, Vec3{0, 0, 0}},
+		{Vec3{0, 0, 0}, Vec3{0, 0, -3}},
+		{Vec3{3, 0, -1}, Vec3{1, 0, 0}},
+		{Vec3{3, 2, 0}, Vec3{0, 2, 0}},
+	}
+	for _, tt := range tests {
+		if got := m.MulPoint(tt.in); !nearVec3(got, tt.want) {
+			t.Errorf("LookAt * %v = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPerspective(t *testing.T) {
+	m := Perspective(Radians(90), 2, 1, 10)
+	want := Mat4{
+		0.5, 0, 0, 0,
+		0, 1, 0, 0,
+		0, 0, -11.0 / 9, -1,
+		0, 0, -20.0 / 9, 0,
+	}
+	if !nearMat4(m, want) {
+		t.Errorf("Perspective = %v, want %v", m, want)
+	}
+	tests := []struct{ in, want Vec3 }{
+		{Vec3{0, 0, -1}, Vec3{0, 0, -1}},       // Near plane
+		{Vec3{0, 0, -10}, Vec3{0, 0, 1}},       // Far plane
+		{Vec3{2, 1, -1}, Vec3{1, 1, -1}},       // Top right corner at near
+		{Vec3{-20, -10, -10}, Vec3{-1, -1, 1}}, // Bottom left corner at far
+	}
+	for _, tt := range tests {
+		if got := m.MulPoint(tt.in); !nearVec3(got, tt.want) {
+			t.Errorf("Perspective * %v = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestOrtho(t *testing.T) {
+	m := Ortho(-2, 6, -1, 3, 1, 5)
+	tests := []struct{ in, want Vec3 }{
+		{Vec3{-2, -1, -1}, Vec3{-1, -1, -1}},
+		{Vec3{6, 3, -5}, Vec3{1, 1, 1}},
+		{Vec3{2, 1, -3}, Vec3{0, 0, 0}},
+	}
+	for _, tt := range tests {
+		if got := m.MulPoint(tt.in); !nearVec3(got, tt.want) {
+			t.Errorf("Ortho * %v = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTRS(t *testing.T) {
+	tr, r, s := Vec3{1, 2, 3}, QuatAxisAngle(Vec3{0, 0, 1}, math.Pi/2), Vec3{2, 3, 4}
+	want := Translate(tr).Mul(r.Mat4()).Mul(Scale(s))
+	if m := TRS(tr, r, s); !nearMat4(m, want) {
+		t.Errorf("TRS = %v, want %v", m, want)
+	}
+	// Scale X by 2, rotate to +Y, translate
+	if got := TRS(tr, r, s).MulPoint(Vec3{1, 0, 0}); !nearVec3(got, Vec3{1, 4, 3}) {
+		t.Errorf("TRS * X = %v, want %v", got, Vec3{1, 4, 3})
+	}
+}
+
+func TestAngles(t *testing.T) {
+	if r := Radians(180); !near(r, math.Pi) {
+		t.Errorf("Radians(180) = %v", r)
+	}
+	if d := Degrees(math.Pi / 2); !near(d, 90) {
+		t.Errorf("Degrees(pi/2) = %v", d)
+	}
+	if Clamp(-1, 0, 1) != 0 || Clamp(2, 0, 1) != 1 || Clamp(0.5, 0, 1) != 0.5 {
+		t.Error("Clamp does not limit to the range")
+	}
+}
@@ -0,0 +1,82 @@
+// Package mathx provides vectors, matrices and quaternions to build the
+// transforms used in shaders. Types are arrays of float32 with the memory
+// layout expected by GL: matrices are column-major, so a Mat4 can be passed
+// as it is to glad uniform setters or uploaded in a buffer. Conventions are
+// the ones of OpenGL: right-handed coordinates, camera looking toward -Z and
+// clip space depth in [-1, 1]. Angles are in radians
+package mathx
+
+import "math"
+
+// Vec2 is a 2D vector
+type Vec2 [2]float32
+
+// Vec3 is a 3D vector
+type Vec3 [3]float32
+
+// Vec4 is a 4D vector, or a 3D point in homogeneous coordinates
+type Vec4 [4]float32
+
+func sqrt(x float32) float32 { return float32(math.Sqrt(float64(x))) }
+
+func (a Vec2) Add(b Vec2) Vec2             { return Vec2{a[0] + b[0], a[1] + b[1]} }
+func (a Vec2) Sub(b Vec2) Vec2             { return Vec2{a[0] - b[0], a[1] - b[1]} }
+func (a Vec2) Mul(k float32) Vec2          { return Vec2{a[0] * k, a[1] * k} }
+func (a Vec2) Dot(b Vec2) float32          { return a[0]*b[0] + a[1]*b[1] }
+func (a Vec2) Len() float32                { return sqrt(a.Dot(a)) }
+func (a Vec2) Lerp(b Vec2, t float32) Vec2 { return a.Add(b.Sub(a).Mul(t)) }
+
+// Normalize returns the vector scaled to unit length, or a zero vector unchanged
+func (a Vec2) Normalize() Vec2 {
+	if l := a.Len(); l != 0 {
+		return a.Mul(1 / l)
+	}
+	return a
+}
+
+func (a Vec3) Add(b Vec3) Vec3             { return Vec3{a[0] + b[0], a[1] + b[1], a[2] + b[2]} }
+func (a Vec3) Sub(b Vec3) Vec3             { return Vec3{a[0] - b[0], a[1] - b[1], a[2] - b[2]} }
+func (a Vec3) Mul(k float32) Vec3          { return Vec3{a[0] * k, a[1] * k, a[2] * k} }
+func (a Vec3) Dot(b Vec3) float32          { return a[0]*b[0] + a[1]*b[1] + a[2]*b[2] }
+func (a Vec3) Len() float32                { return sqrt(a.Dot(a)) }
+func (a Vec3) Lerp(b Vec3, t float32) Vec3 { return a.Add(b.Sub(a).Mul(t)) }
+
+// MulVec returns the component-wise product
+func (a Vec3) MulVec(b Vec3) Vec3 { return Vec3{a[0] * b[0], a[1] * b[1], a[2] * b[2]} }
+
+// Cross returns the cross product a x b
+func (a Vec3) Cross(b Vec3) Vec3 {
+	return Vec3{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+}
+
+// Normalize returns the vector scaled to unit length, or a zero vector unchanged
+func (a Vec3) Normalize() Vec3 {
+	if l := a.Len(); l != 0 {
+		return a.Mul(1 / l)
+	}
+	return a
+}
+
+// Vec4 returns the vector extended with w
+func (a Vec3) Vec4(w float32) Vec4 { return Vec4{a[0], a[1], a[2], w} }
+
+func (a Vec4) Add(b Vec4) Vec4             { return Vec4{a[0] + b[0], a[1] + b[1], a[2] + b[2], a[3] + b[3]} }
+func (a Vec4) Sub(b Vec4) Vec4             { return Vec4{a[0] - b[0], a[1] - b[1], a[2] - b[2], a[3] - b[3]} }
+func (a Vec4) Mul(k float32) Vec4          { return Vec4{a[0] * k, a[1] * k, a[2] * k, a[3] * k} }
+func (a Vec4) Dot(b Vec4) float32          { return a[0]*b[0] + a[1]*b[1] + a[2]*b[2] + a[3]*b[3] }
+func (a Vec4) Len() float32                { return sqrt(a.Dot(a)) }
+func (a Vec4) Lerp(b Vec4, t float32) Vec4 { return a.Add(b.Sub(a).Mul(t)) }
+
+// Normalize returns the vector scaled to unit length, or a zero vector unchanged
+func (a Vec4) Normalize() Vec4 {
+	if l := a.Len(); l != 0 {
+		return a.Mul(1 / l)
+	}
+	return a
+}
+
+// Vec3 returns the first three components
+func (a Vec4) Vec3() Vec3 { return Vec3{a[0], a[1], a[2]} }
+
+// Project returns the 3D point, dividing by w
+func (a Vec4) Project() Vec3 { return Vec3{a[0] / a[3], a[1] / a[3], a[2] / a[3]} }
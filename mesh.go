@@ -0,0 +1,211 @@
+package glad
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"unsafe"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+)
+
+// Mesh owns the objects needed to draw some geometry: a VAO, a VBO with
+// interleaved vertices and an optional element buffer with 32 bit indices.
+// The mesh is not tied to a program: the attributes of the layout get fixed
+// locations, in order (the first attribute has location 0, and so on), so
+// the same mesh can be drawn by any program using those locations, either
+// with layout(location = N) in the shader or with BindAttributeLocations
+type Mesh struct {
+	VAO       VertexArrayObject
+	VBO       VertexBufferObject
+	EBO       VertexBufferObject // Buffer of the indices, 0 if not indexed
+	Layout    *VertexLayout
+	Mode      uint32    // Primitives to draw, e.g. gl.TRIANGLES
+	NumVert   int32     // Number of vertices in the VBO
+	NumIndex  int32     // Number of indices in the EBO
+	SubMeshes []SubMesh // Optional named ranges of the mesh
+	Min, Max  [3]float32
+	usage     uint32
+}
+
+// SubMesh is a range of a mesh that can be drawn separately, e.g. the
+// part of a model using a certain material. First and Count refer to
+// indices if the mesh is indexed, to vertices otherwise
+type SubMesh struct {
+	Name         string
+	First, Count int32
+}
+
+// NewMesh creates a mesh with the given vertices and indices
+// vertices must be a slice of structs, whose layout is derived with LayoutOf
+// indices can be nil to draw the vertices in order. usage is used for both
+// buffers, see BufferData32
+func NewMesh(mode uint32, vertices interface{}, indices []uint32, usage uint32) (*Mesh, error) {
+	layout, err := LayoutOf(vertices)
+	if err != nil {
+		return nil, err
+	}
+	m := Mesh{
+		VAO:    NewVertexArrayObject(),
+		VBO:    NewVertexBufferObject(),
+		Layout: layout,
+		Mode:   mode,
+		usage:  usage,
+	}
+	m.VAO.VertexBuffer(0, m.VBO, 0, layout.Stride)
+	for i, at := range layout.Attribs {
+		m.VAO.attribSpec(0, VertexAttrib(i), at)
+	}
+	if err := m.Update(vertices); err != nil {
+		m.Delete()
+		return nil, err
+	}
+	if indices != nil {
+		m.EBO = NewVertexBufferObject()
+		m.VAO.ElementBuffer(m.EBO)
+		m.UpdateIndices(indices)
+	}
+	return &m, nil
+}
+
+// BindAttributeLocations sets the locations of the attributes of the mesh
+// in the program. Call this before linking the program
+func (m *Mesh) BindAttributeLocations(pr Program) {
+	for i, at := range m.Layout.Attribs {
+		pr.BindAttributeLocation(uint32(i), at.Name)
+	}
+}
+
+// AttribLocation returns the location of the named attribute in the mesh
+// or -1 if there is no such attribute
+func (m *Mesh) AttribLocation(name string) int {
+	for i, at := range m.Layout.Attribs {
+		if at.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Update replaces the vertices of the mesh, recomputing the bounding box
+// vertices must have the same type used to create the mesh. If the number of
+// vertices is unchanged, the data is replaced without reallocating storage
+func (m *Mesh) Update(vertices interface{}) error {
+	layout, err := LayoutOf(vertices)
+	if err != nil {
+		return err
+	}
+	if reflect.TypeOf(vertices).Kind() != reflect.Slice {
+		return fmt.Errorf("mesh vertices must be a slice, got %T", vertices)
+	}
+	if layout.Stride != m.Layout.Stride || len(layout.Attribs) != len(m.Layout.Attribs) {
+		return errors.New("mesh Update requires vertices of the same type")
+	}
+	ptr, size := sliceData(vertices)
+	n := int32(size / int(layout.Stride))
+	if n == m.NumVert && n > 0 {
+		m.VBO.BufferSubDataStructs(vertices, 0)
+	} else {
+		m.VBO.BufferDataStructs(vertices, m.usage)
+	}
+	m.NumVert = n
+	m.Min, m.Max = m.bounds(ptr)
+	return nil
+}
+
+// UpdateIndices replaces the indices of an indexed mesh
+func (m *Mesh) UpdateIndices(indices []uint32) {
+	if m.EBO == 0 {
+		panic("Mesh UpdateIndices on a mesh without indices")
+	}
+	if int32(len(indices)) == m.NumIndex && len(indices) > 0 {
+		m.EBO.BufferSubDataStructs(indices, 0)
+	} else {
+		m.EBO.BufferDataStructs(indices, m.usage)
+	}
+	m.NumIndex = int32(len(indices))
+}
+
+// bounds computes the bounding box of the vertices in memory, using the first
+// float attribute of the layout as position. Missing coordinates are zero
+func (m *Mesh) bounds(ptr unsafe.Pointer) (min, max [3]float32) {
+	if m.NumVert == 0 {
+		return
+	}
+	var pos *AttribSpec
+	for i := range m.Layout.Attribs {
+		if m.Layout.Attribs[i].Type == gl.FLOAT {
+			pos = &m.Layout.Attribs[i]
+			break
+		}
+	}
+	if pos == nil {
+		return
+	}
+	comps := int(pos.Size)
+	if comps > 3 {
+		comps = 3
+	}
+	for c := 0; c < comps; c++ {
+		min[c], max[c] = math.MaxFloat32, -math.MaxFloat32
+	}
+	for v := 0; v < int(m.NumVert); v++ {
+		off := uintptr(v)*uintptr(m.Layout.Stride) + uintptr(pos.Offset)
+		for c := 0; c < comps; c++ {
+			x := *(*float32)(unsafe.Pointer(uintptr(ptr) + off + uintptr(c*4)))
+			if x < min[c] {
+				min[c] = x
+			}
+			if x > max[c] {
+				max[c] = x
+			}
+		}
+	}
+	return
+}
+
+// Draw draws the whole mesh with the program in use
+func (m *Mesh) Draw() {
+	if m.EBO != 0 {
+		m.DrawRange(0, m.NumIndex)
+	} else {
+		m.DrawRange(0, m.NumVert)
+	}
+}
+
+// DrawRange draws count vertices (or indices) starting from first
+func (m *Mesh) DrawRange(first, count int32) {
+	checkThread()
+	m.VAO.Bind()
+	if m.EBO != 0 {
+		gl.DrawElements(m.Mode, count, gl.UNSIGNED_INT, gl.PtrOffset(int(first)*4))
+	} else {
+		gl.DrawArrays(m.Mode, first, count)
+	}
+}
+
+// DrawSubMesh draws the i-th sub mesh
+func (m *Mesh) DrawSubMesh(i int) {
+	m.DrawRange(m.SubMeshes[i].First, m.SubMeshes[i].Count)
+}
+
+// DrawInstanced draws the whole mesh many times, see BindingDivisor
+func (m *Mesh) DrawInstanced(instances int32) {
+	checkThread()
+	m.VAO.Bind()
+	if m.EBO != 0 {
+		gl.DrawElementsInstanced(m.Mode, m.NumIndex, gl.UNSIGNED_INT, nil, instances)
+	} else {
+		gl.DrawArraysInstanced(m.Mode, 0, m.NumVert, instances)
+	}
+}
+
+// Delete frees the objects owned by the mesh
+func (m *Mesh) Delete() {
+	m.VAO.Delete()
+	m.VBO.Delete()
+	if m.EBO != 0 {
+		m.EBO.Delete()
+	}
+}
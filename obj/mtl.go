@@ -0,0 +1,175 @@
+package obj
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Material is a material described in a MTL file
+// Texture paths are empty if the map is not specified, they can be loaded
+// with glad.LoadTexture
+type Material struct {
+	Name      string
+	Ambient   [3]float32 // Ka
+	Diffuse   [3]float32 // Kd
+	Specular  [3]float32 // Ks
+	Emissive  [3]float32 // Ke
+	Shininess float32    // Ns, specular exponent
+	Opacity   float32    // d, or 1 - Tr
+	IOR       float32    // Ni, index of refraction
+	Illum     int        // Illumination model
+
+	AmbientMap   string // map_Ka
+	DiffuseMap   string // map_Kd
+	SpecularMap  string // map_Ks
+	EmissiveMap  string // map_Ke
+	ShininessMap string // map_Ns
+	OpacityMap   string // map_d
+	BumpMap      string // map_Bump or bump
+	NormalMap    string // norm
+}
+
+// ParseMTL reads the materials in a MTL file
+func ParseMTL(r io.Reader) ([]*Material, error) {
+	var mats []*Material
+	var cur *Material
+	sc := bufio.NewScanner(r)
+	line := 0
+	for sc.Scan() {
+		line++
+		text := sc.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "newmtl" {
+			cur = &Material{Name: strings.Join(fields[1:], " "), Opacity: 1, IOR: 1, Diffuse: [3]float32{1, 1, 1}}
+			mats = append(mats, cur)
+			continue
+		}
+		if cur == nil {
+			return nil, fmt.Errorf("mtl: line %d: %s before newmtl", line, fields[0])
+		}
+		if err := cur.parse(strings.ToLower(fields[0]), fields[1:]); err != nil {
+			return nil, fmt.Errorf("mtl: line %d: %v", line, err)
+		}
+	}
+	return mats, sc.Err()
+}
+
+// parse sets a property of the material
+func (m *Material) parse(key string, args []string) error {
+	var err error
+	switch key {
+	case "ka":
+		m.Ambient, err = parseColor(args)
+	case "kd":
+		m.Diffuse, err = parseColor(args)
+	case "ks":
+		m.Specular, err = parseColor(args)
+	case "ke":
+		m.Emissive, err = parseColor(args)
+	case "ns":
+		m.Shininess, err = parseScalar(args)
+	case "ni":
+		m.IOR, err = parseScalar(args)
+	case "d":
+		m.Opacity, err = parseScalar(args)
+	case "tr":
+		var tr float32
+		tr, err = parseScalar(args)
+		m.Opacity = 1 - tr
+	case "illum":
+		if len(args) == 0 {
+			return fmt.Errorf("missing illumination model")
+		}
+		m.Illum, err = strconv.Atoi(args[0])
+	case "map_ka":
+		m.AmbientMap, err = mapPath(args)
+	case "map_kd":
+		m.DiffuseMap, err = mapPath(args)
+	case "map_ks":
+		m.SpecularMap, err = mapPath(args)
+	case "map_ke":
+		m.EmissiveMap, err = mapPath(args)
+	case "map_ns":
+		m.ShininessMap, err = mapPath(args)
+	case "map_d":
+		m.OpacityMap, err = mapPath(args)
+	case "map_bump", "bump":
+		m.BumpMap, err = mapPath(args)
+	case "norm", "map_kn":
+		m.NormalMap, err = mapPath(args)
+	}
+	// Other properties (e.g. Tf, reflection maps) are ignored
+	return err
+}
+
+// maps returns pointers to all the texture paths of the material
+func (m *Material) maps() []*string {
+	return []*string{&m.AmbientMap, &m.DiffuseMap, &m.SpecularMap, &m.EmissiveMap,
+		&m.ShininessMap, &m.OpacityMap, &m.BumpMap, &m.NormalMap}
+}
+
+// resolvePaths joins dir to the relative texture paths
+func (m *Material) resolvePaths(dir string) {
+	for _, p := range m.maps() {
+		if *p != "" && !filepath.IsAbs(*p) {
+			*p = filepath.Join(dir, filepath.FromSlash(*p))
+		}
+	}
+}
+
+// mapOptions is the number of arguments of the options of texture maps
+var mapOptions = map[string]int{
+	"-blendu": 1, "-blendv": 1, "-bm": 1, "-boost": 1, "-cc": 1, "-clamp": 1,
+	"-imfchan": 1, "-mm": 2, "-o": 3, "-s": 3, "-t": 3, "-texres": 1, "-type": 1,
+}
+
+// mapPath returns the file name of a texture map, skipping the options
+// The name can contain spaces
+func mapPath(args []string) (string, error) {
+	i := 0
+	for i < len(args) {
+		n, ok := mapOptions[strings.ToLower(args[i])]
+		if !ok {
+			break
+		}
+		i++
+		// Options -o, -s and -t have 1 to 3 numeric arguments
+		for k := 0; k < n && i < len(args); k++ {
+			if _, err := strconv.ParseFloat(args[i], 32); err != nil && n == 3 && k > 0 {
+				break
+			}
+			i++
+		}
+	}
+	if i >= len(args) {
+		return "", fmt.Errorf("missing texture file name")
+	}
+	return strings.Join(args[i:], " "), nil
+}
+
+func parseColor(args []string) ([3]float32, error) {
+	if len(args) > 0 && (args[0] == "spectral" || args[0] == "xyz") {
+		return [3]float32{}, fmt.Errorf("unsupported color %s", args[0])
+	}
+	c, err := parseFloats(args, 1)
+	if err == nil && len(args) == 1 {
+		// A single value is used for all components
+		c[1], c[2] = c[0], c[0]
+	}
+	return c, err
+}
+
+func parseScalar(args []string) (float32, error) {
+	v, err := parseFloats(args, 1)
+	return v[0], err
+}
@@ -0,0 +1,354 @@
+// Package obj loads Wavefront OBJ models and their MTL materials
+// The model is converted to a single indexed mesh with interleaved vertices
+// (see Vertex) ready to be uploaded with glad.NewMesh or a VertexBufferObject,
+// and a list of groups, one for each range of faces using the same material.
+// Parsing is pure Go and does not require an OpenGL context
+package obj
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Vertex is a vertex of the model, its layout can be used with glad.NewMesh
+type Vertex struct {
+	Pos    [3]float32 `glad:"pos"`
+	Normal [3]float32 `glad:"normal"`
+	UV     [2]float32 `glad:"uv"`
+}
+
+// Group is a range of the indices of the model sharing the same material
+// First and Count can be used as a glad.SubMesh
+type Group struct {
+	Name         string // Name of the group or object (g and o statements)
+	Material     string // Name of the material, empty if none
+	First, Count int32  // Range in the indices of the model
+}
+
+// Model is a triangulated model, where each distinct combination of
+// position, texture coordinate and normal is a vertex
+type Model struct {
+	Vertices  []Vertex
+	Indices   []uint32
+	Groups    []Group
+	Materials map[string]*Material
+	HasUVs    bool // True if texture coordinates were specified in the file
+}
+
+// Indices16 returns the indices as uint16 if the model is small enough
+// ok is false if the model has too many vertices for 16 bit indices
+func (m *Model) Indices16() (indices []uint16, ok bool) {
+	if len(m.Vertices) > math.MaxUint16+1 {
+		return nil, false
+	}
+	indices = make([]uint16, len(m.Indices))
+	for i, idx := range m.Indices {
+		indices[i] = uint16(idx)
+	}
+	return indices, true
+}
+
+// Load reads an OBJ file and the MTL files it references, which are looked
+// for in the same directory. Texture paths of materials are made relative
+// to the working directory, so that they can be opened directly
+func Load(path string) (*Model, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	dir := filepath.Dir(path)
+	return Parse(f, func(name string) (io.ReadCloser, error) {
+		return os.Open(filepath.Join(dir, name))
+	}, dir)
+}
+
+// vertexKey identifies a distinct vertex: indices of position, texture
+// coordinate and normal (-1 if missing) and, when the normal is missing,
+// the smoothing group that will be used to compute it, or the face for flat
+// shading (face is 0 for smoothed vertices, so the two never collide)
+type vertexKey struct {
+	v, vt, vn int
+	smooth    int
+	face      int
+}
+
+// parser holds the state while reading an OBJ file
+type parser struct {
+	model    Model
+	pos      [][3]float32
+	uvs      [][2]float32
+	normals  [][3]float32
+	vertices map[vertexKey]uint32
+	computed []bool // Vertices whose normal must be computed
+	smooth   int    // Current smoothing group, 0 if off
+	faces    int    // Number of faces read, used for flat shading
+	group    string
+	material string
+	openMTL  func(name string) (io.ReadCloser, error)
+	mtlDir   string
+}
+
+// Parse reads an OBJ model from r. openMTL is used to read the files in
+// mtllib statements, it can be nil to ignore materials. mtlDir is joined to
+// the texture paths of materials
+func Parse(r io.Reader, openMTL func(name string) (io.ReadCloser, error), mtlDir string) (*Model, error) {
+	p := parser{
+		vertices: make(map[vertexKey]uint32),
+		openMTL:  openMTL,
+		mtlDir:   mtlDir,
+	}
+	p.model.Materials = make(map[string]*Material)
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for sc.Scan() {
+		line++
+		if err := p.parseLine(sc.Text()); err != nil {
+			return nil, fmt.Errorf("obj: line %d: %v", line, err)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	p.closeGroup()
+	p.computeNormals()
+	return &p.model, nil
+}
+
+func (p *parser) parseLine(text string) error {
+	if i := strings.IndexByte(text, '#'); i >= 0 {
+		text = text[:i]
+	}
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return nil
+	}
+	args := fields[1:]
+	switch fields[0] {
+	case "v":
+		v, err := parseFloats(args, 3)
+		if err != nil {
+			return err
+		}
+		p.pos = append(p.pos, [3]float32{v[0], v[1], v[2]})
+	case "vt":
+		v, err := parseFloats(args, 1)
+		if err != nil {
+			return err
+		}
+		p.uvs = append(p.uvs, [2]float32{v[0], v[1]})
+	case "vn":
+		v, err := parseFloats(args, 3)
+		if err != nil {
+			return err
+		}
+		p.normals = append(p.normals, [3]float32{v[0], v[1], v[2]})
+	case "f":
+		return p.parseFace(args)
+	case "g", "o":
+		p.closeGroup()
+		p.group = strings.Join(args, " ")
+	case "usemtl":
+		p.closeGroup()
+		p.material = strings.Join(args, " ")
+	case "s":
+		p.smooth = 0
+		if len(args) > 0 && args[0] != "off" {
+			n, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid smoothing group %q", args[0])
+			}
+			p.smooth = n
+		}
+	case "mtllib":
+		if p.openMTL == nil {
+			return nil
+		}
+		for _, name := range args {
+			if err := p.loadMTL(name); err != nil {
+				return err
+			}
+		}
+	}
+	// Other statements (e.g. lines, curves) are ignored
+	return nil
+}
+
+// loadMTL reads the materials of a MTL file
+func (p *parser) loadMTL(name string) error {
+	f, err := p.openMTL(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	mats, err := ParseMTL(f)
+	if err != nil {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+	for _, m := range mats {
+		if p.mtlDir != "" {
+			m.resolvePaths(p.mtlDir)
+		}
+		p.model.Materials[m.Name] = m
+	}
+	return nil
+}
+
+// closeGroup ends the current range of faces, starting a new one
+func (p *parser) closeGroup() {
+	first := int32(0)
+	if n := len(p.model.Groups); n > 0 {
+		first = p.model.Groups[n-1].First + p.model.Groups[n-1].Count
+	}
+	count := int32(len(p.model.Indices)) - first
+	if count > 0 {
+		p.model.Groups = append(p.model.Groups, Group{p.group, p.material, first, count})
+	}
+}
+
+// parseFace adds the triangles of a face, triangulating polygons as a fan
+// (this assumes that polygons are convex)
+func (p *parser) parseFace(args []string) error {
+	if len(args) < 3 {
+		return fmt.Errorf("face with %d vertices", len(args))
+	}
+	p.faces++
+	idx := make([]uint32, len(args))
+	for i, a := range args {
+		v, err := p.vertex(a)
+		if err != nil {
+			return err
+		}
+		idx[i] = v
+	}
+	for i := 1; i+1 < len(idx); i++ {
+		p.model.Indices = append(p.model.Indices, idx[0], idx[i], idx[i+1])
+	}
+	return nil
+}
+
+// vertex returns the index of the vertex described by a face element
+// (v, v/vt, v//vn or v/vt/vn) creating it if needed
+func (p *parser) vertex(elem string) (uint32, error) {
+	parts := strings.Split(elem, "/")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid face element %q", elem)
+	}
+	key := vertexKey{v: -1, vt: -1, vn: -1}
+	var err error
+	if key.v, err = resolveIndex(parts[0], len(p.pos)); err != nil {
+		return 0, err
+	}
+	if len(parts) > 1 && parts[1] != "" {
+		if key.vt, err = resolveIndex(parts[1], len(p.uvs)); err != nil {
+			return 0, err
+		}
+	}
+	if len(parts) > 2 && parts[2] != "" {
+		if key.vn, err = resolveIndex(parts[2], len(p.normals)); err != nil {
+			return 0, err
+		}
+	}
+	if key.vn < 0 {
+		// Normals will be averaged among vertices of the same smoothing group,
+		// without smoothing each face has its own vertices
+		key.smooth = p.smooth
+		if p.smooth == 0 {
+			key.face = p.faces
+		}
+	}
+	if idx, ok := p.vertices[key]; ok {
+		return idx, nil
+	}
+
+	v := Vertex{Pos: p.pos[key.v]}
+	if key.vt >= 0 {
+		v.UV = p.uvs[key.vt]
+		p.model.HasUVs = true
+	}
+	if key.vn >= 0 {
+		v.Normal = p.normals[key.vn]
+	}
+	idx := uint32(len(p.model.Vertices))
+	p.model.Vertices = append(p.model.Vertices, v)
+	p.computed = append(p.computed, key.vn < 0)
+	p.vertices[key] = idx
+	return idx, nil
+}
+
+// computeNormals sets the normals missing in the file, averaging the
+// normals of the faces sharing each vertex, weighted by their area
+func (p *parser) computeNormals() {
+	verts := p.model.Vertices
+	acc := make([][3]float64, len(verts))
+	for i := 0; i+2 < len(p.model.Indices); i += 3 {
+		a, b, c := p.model.Indices[i], p.model.Indices[i+1], p.model.Indices[i+2]
+		var e1, e2 [3]float64
+		for k := 0; k < 3; k++ {
+			e1[k] = float64(verts[b].Pos[k] - verts[a].Pos[k])
+			e2[k] = float64(verts[c].Pos[k] - verts[a].Pos[k])
+		}
+		n := [3]float64{
+			e1[1]*e2[2] - e1[2]*e2[1],
+			e1[2]*e2[0] - e1[0]*e2[2],
+			e1[0]*e2[1] - e1[1]*e2[0],
+		}
+		for _, v := range [3]uint32{a, b, c} {
+			for k := 0; k < 3; k++ {
+				acc[v][k] += n[k]
+			}
+		}
+	}
+	for i := range verts {
+		if !p.computed[i] {
+			continue
+		}
+		n := acc[i]
+		l := math.Sqrt(n[0]*n[0] + n[1]*n[1] + n[2]*n[2])
+		if l > 0 {
+			verts[i].Normal = [3]float32{float32(n[0] / l), float32(n[1] / l), float32(n[2] / l)}
+		}
+	}
+}
+
+// resolveIndex converts a 1-based (or negative, relative to the end) OBJ
+// index to a 0-based index in a list of n elements
+func resolveIndex(s string, n int) (int, error) {
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid index %q", s)
+	}
+	if i < 0 {
+		i = n + i
+	} else {
+		i--
+	}
+	if i < 0 || i >= n {
+		return 0, fmt.Errorf("index %s out of range", s)
+	}
+	return i, nil
+}
+
+// parseFloats parses the fields as floats, requiring at least min of them
+// Missing values up to 3 are set to 0
+func parseFloats(fields []string, min int) ([3]float32, error) {
+	var v [3]float32
+	if len(fields) < min {
+		return v, fmt.Errorf("expected at least %d values, got %d", min, len(fields))
+	}
+	for i := 0; i < len(fields) && i < 3; i++ {
+		f, err := strconv.ParseFloat(fields[i], 32)
+		if err != nil {
+			return v, fmt.Errorf("invalid number %q", fields[i])
+		}
+		v[i] = float32(f)
+	}
+	return v, nil
+}
@@ -0,0 +1,226 @@
+package obj
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// parse parses an OBJ model from a string, with no materials
+func parse(t *testing.T, src string) *Model {
+	t.Helper()
+	m, err := Parse(strings.NewReader(src), nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestParseFaces(t *testing.T) {
+	const verts = "v 0 0 0\nv 1 0 0\nv 1 1 0\nv 0 1 0\nvt 0 0\nvt 1 0\nvt 1 1\nvn 0 0 1\n"
+	tests := []struct {
+		name     string
+		faces    string
+		vertices int
+		indices  []uint32
+		uvs      bool
+	}{
+		{"Positions", "f 1 2 3\n", 3, []uint32{0, 1, 2}, false},
+		{"Negative", "f -4 -3 -2\n", 3, []uint32{0, 1, 2}, false},
+		{"PosUV", "f 1/1 2/2 3/3\n", 3, []uint32{0, 1, 2}, true},
+		{"PosNormal", "f 1//1 2//1 3//1\n", 3, []uint32{0, 1, 2}, false},
+		{"PosUVNormal", "f 1/1/1 2/2/1 3/3/-1\n", 3, []uint32{0, 1, 2}, true},
+		{"Quad", "f 1//1 2//1 3//1 4//1\n", 4, []uint32{0, 1, 2, 0, 2, 3}, false},
+		{"Shared", "f 1//1 2//1 3//1\nf 1//1 3//1 4//1\n", 4, []uint32{0, 1, 2, 0, 2, 3}, false},
+		{"DistinctUV", "f 1/1/1 2/2/1 3/3/1\nf 1/2/1 3/3/1 4/1/1\n", 5, []uint32{0, 1, 2, 3, 2, 4}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := parse(t, verts+tt.faces)
+			if len(m.Vertices) != tt.vertices {
+				t.Errorf("got %d vertices, want %d", len(m.Vertices), tt.vertices)
+			}
+			if fmt.Sprint(m.Indices) != fmt.Sprint(tt.indices) {
+				t.Errorf("got indices %v, want %v", m.Indices, tt.indices)
+			}
+			if m.HasUVs != tt.uvs {
+				t.Errorf("got HasUVs %v, want %v", m.HasUVs, tt.uvs)
+			}
+			for i, v := range m.Vertices {
+				if v.Normal != [3]float32{0, 0, 1} {
+					t.Errorf("vertex %d: got normal %v, want +Z", i, v.Normal)
+				}
+			}
+		})
+	}
+}
+
+func TestSmoothing(t *testing.T) {
+	// Two triangles sharing an edge, facing +Z and -X
+	const verts = "v 0 0 0\nv 1 0 0\nv 0 1 0\nv 0 0 -1\n"
+	tests := []struct {
+		name     string
+		faces    string
+		vertices int
+	}{
+		{"Flat", "s off\nf 1 2 3\nf 1 3 4\n", 6},
+		{"Smooth", "s 1\nf 1 2 3\nf 1 3 4\n", 4},
+		{"Groups", "s 1\nf 1 2 3\ns 2\nf 1 3 4\n", 6},
+		// A flat face must not share vertices with a group of negative id
+		{"FlatNegativeGroup", "f 1 2 3\ns -1\nf 1 3 4\n", 6},
+		{"NegativeGroupFlat", "s -2\nf 1 2 3\ns 0\nf 1 3 4\nf 1 2 3\n", 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := parse(t, verts+tt.faces)
+			if len(m.Vertices) != tt.vertices {
+				t.Errorf("got %d vertices, want %d", len(m.Vertices), tt.vertices)
+			}
+		})
+	}
+
+	m := parse(t, verts+"s 1\nf 1 2 3\nf 1 3 4\n")
+	const k = 0.70710677 // Both faces have the same area
+	if n := m.Vertices[0].Normal; n != [3]float32{-k, 0, k} {
+		t.Errorf("shared vertex: got normal %v, want the average %v", n, [3]float32{-k, 0, k})
+	}
+	if n := m.Vertices[1].Normal; n != [3]float32{0, 0, 1} {
+		t.Errorf("got normal %v, want +Z", n)
+	}
+}
+
+func TestGroups(t *testing.T) {
+	const src = `mtllib scene.mtl
+v 0 0 0
+v 1 0 0
+v 0 1 0
+o first
+usemtl red
+f 1 2 3
+f 1 2 3
+usemtl blue
+f 1 2 3
+g second
+f 1 2 3
+`
+	const mtl = `newmtl red
+Kd 1 0 0
+map_Kd -s 1 1 1 tex/red.png
+newmtl blue
+Kd 0 0 1
+`
+	var opened []string
+	m, err := Parse(strings.NewReader(src), func(name string) (io.ReadCloser, error) {
+		opened = append(opened, name)
+		return ioutil.NopCloser(strings.NewReader(mtl)), nil
+	}, "models")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(opened) != "[scene.mtl]" {
+		t.Errorf("opened %v, want [scene.mtl]", opened)
+	}
+	want := []Group{{"first", "red", 0, 6}, {"first", "blue", 6, 3}, {"second", "blue", 9, 3}}
+	if fmt.Sprint(m.Groups) != fmt.Sprint(want) {
+		t.Errorf("got groups %v, want %v", m.Groups, want)
+	}
+	red := m.Materials["red"]
+	if red == nil || m.Materials["blue"] == nil {
+		t.Fatalf("got materials %v, want red and blue", m.Materials)
+	}
+	if want := filepath.Join("models", "tex", "red.png"); red.DiffuseMap != want {
+		t.Errorf("got diffuse map %q, want %q", red.DiffuseMap, want)
+	}
+}
+
+func TestParseMTL(t *testing.T) {
+	const src = `# Materials
+newmtl metal
+Ka 0.1
+Kd 0.5 0.6 0.7
+Ns 250
+Tr 0.25
+illum 2
+map_Bump -bm 0.5 normal map.png
+norm n.png
+
+newmtl plain
+`
+	mats, err := ParseMTL(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mats) != 2 {
+		t.Fatalf("got %d materials, want 2", len(mats))
+	}
+	m := mats[0]
+	want := Material{
+		Name:      "metal",
+		Ambient:   [3]float32{0.1, 0.1, 0.1},
+		Diffuse:   [3]float32{0.5, 0.6, 0.7},
+		Shininess: 250,
+		Opacity:   0.75,
+		IOR:       1,
+		Illum:     2,
+		BumpMap:   "normal map.png",
+		NormalMap: "n.png",
+	}
+	if *m != want {
+		t.Errorf("got %+v, want %+v", *m, want)
+	}
+	if p := mats[1]; p.Name != "plain" || p.Opacity != 1 || p.Diffuse != [3]float32{1, 1, 1} {
+		t.Errorf("got %+v, want the defaults", *p)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		name, src, err string
+	}{
+		{"ShortVertex", "v 1 2\n", "line 1: expected at least 3 values"},
+		{"BadNumber", "v 1 x 2\n", `line 1: invalid number "x"`},
+		{"ShortFace", "v 0 0 0\nf 1 1\n", "line 2: face with 2 vertices"},
+		{"OutOfRange", "v 0 0 0\nf 1 1 2\n", "line 2: index 2 out of range"},
+		{"NegativeOutOfRange", "v 0 0 0\nf 1 1 -2\n", "line 2: index -2 out of range"},
+		{"Zero", "v 0 0 0\nf 0 1 1\n", "line 2: index 0 out of range"},
+		{"MissingUV", "v 0 0 0\nf 1/1 1 1\n", "line 2: index 1 out of range"},
+		{"BadElement", "v 0 0 0\nf 1/1/1/1 1 1\n", `line 2: invalid face element "1/1/1/1"`},
+		{"BadIndex", "v 0 0 0\nf a 1 1\n", `line 2: invalid index "a"`},
+		{"BadSmoothing", "s x\n", `line 1: invalid smoothing group "x"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.src), nil, "")
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("got error %v, want %q", err, tt.err)
+			}
+		})
+	}
+
+	mtlTests := []struct {
+		name, src, err string
+	}{
+		{"BeforeNewmtl", "Kd 1 1 1\n", "line 1: Kd before newmtl"},
+		{"BadColor", "newmtl a\nKd x\n", `line 2: invalid number "x"`},
+		{"Spectral", "newmtl a\nKd spectral file.rfl\n", "line 2: unsupported color spectral"},
+		{"MissingMap", "newmtl a\nmap_Kd -clamp on\n", "line 2: missing texture file name"},
+	}
+	for _, tt := range mtlTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseMTL(strings.NewReader(tt.src))
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("got error %v, want %q", err, tt.err)
+			}
+		})
+	}
+
+	_, err := Parse(strings.NewReader("mtllib missing.mtl\n"), func(name string) (io.ReadCloser, error) {
+		return nil, fmt.Errorf("%s not found", name)
+	}, "")
+	if err == nil || !strings.Contains(err.Error(), "missing.mtl not found") {
+		t.Errorf("got error %v, want missing.mtl not found", err)
+	}
+}
@@ -0,0 +1,141 @@
+package glad
+
+import "math"
+
+// Packed formats reduce the memory used by vertex attributes that do not
+// need full float precision, like normals, texture coordinates and colors.
+// The functions here convert float32 data on the CPU, so that the result
+// can be uploaded with BufferDataStructs and used with AttribFormat.
+
+// Half is a 16 bit floating point number, used with gl.HALF_FLOAT
+type Half uint16
+
+// PackedNormal holds 3 signed normalized components of 10 bits and one of
+// 2 bits, used with gl.INT_2_10_10_10_REV and size 4
+type PackedNormal uint32
+
+// PackedFloat3 holds 3 unsigned floats of 11, 11 and 10 bits, used with
+// gl.UNSIGNED_INT_10F_11F_11F_REV and size 3. Negative values are not representable
+type PackedFloat3 uint32
+
+// packUFloat converts a non-negative float to an unsigned float with 5 bits
+// of exponent and mantBits bits of mantissa, rounding to nearest even
+// Negative values become 0, values too large become infinity
+func packUFloat(f float32, mantBits uint) uint32 {
+	const expBias = 15
+	inf := uint32(0x1f) << mantBits
+	if f != f {
+		return inf | 1 // NaN
+	}
+	if f <= 0 {
+		return 0
+	}
+	b := math.Float32bits(f)
+	exp := int32(b>>23) - 127 + expBias
+	mant := b & 0x7fffff
+	if exp >= 0x1f {
+		return inf
+	}
+
+	var v, shift uint32
+	if exp <= 0 {
+		// Denormalized in the small format: make the implicit bit explicit
+		shift = uint32(int32(24-mantBits) - exp)
+		if shift > 24 {
+			return 0
+		}
+		mant |= 0x800000
+		v = mant >> shift
+	} else {
+		shift = uint32(23 - mantBits)
+		v = uint32(exp)<<mantBits | mant>>shift
+	}
+	// Round to nearest even: a carry in the mantissa correctly increments
+	// the exponent, possibly up to infinity
+	rem, half := mant&(1<<shift-1), uint32(1)<<(shift-1)
+	if rem > half || (rem == half && v&1 == 1) {
+		v++
+	}
+	return v
+}
+
+// NewHalf converts a float32 to the nearest half float
+func NewHalf(f float32) Half {
+	sign := uint16(math.Float32bits(f)>>16) & 0x8000
+	return Half(sign | uint16(packUFloat(float32(math.Abs(float64(f))), 10)))
+}
+
+// Float32 converts the half float to float32
+func (h Half) Float32() float32 {
+	sign := uint32(h&0x8000) << 16
+	exp := uint32(h>>10) & 0x1f
+	mant := uint32(h & 0x3ff)
+	switch exp {
+	case 0:
+		if mant == 0 {
+			return math.Float32frombits(sign)
+		}
+		// Denormalized: normalize it, as it is representable in float32
+		e := uint32(127 - 14)
+		for mant&0x400 == 0 {
+			mant <<= 1
+			e--
+		}
+		return math.Float32frombits(sign | e<<23 | (mant&0x3ff)<<13)
+	case 0x1f:
+		return math.Float32frombits(sign | 0x7f800000 | mant<<13)
+	default:
+		return math.Float32frombits(sign | (exp+127-15)<<23 | mant<<13)
+	}
+}
+
+// PackHalfs converts a slice of float32 (e.g. texture coordinates) to half floats
+func PackHalfs(data []float32) []Half {
+	res := make([]Half, len(data))
+	for i := range data {
+		res[i] = NewHalf(data[i])
+	}
+	return res
+}
+
+// snorm converts f in [-1, 1] to a signed normalized integer of given bits
+func snorm(f float32, bits uint) uint32 {
+	max := float64(int32(1)<<(bits-1) - 1)
+	v := math.Round(math.Max(-1, math.Min(1, float64(f))) * max)
+	return uint32(int32(v)) & (1<<bits - 1)
+}
+
+// NewPackedNormal packs the components, each in [-1, 1], in the 2_10_10_10 format
+func NewPackedNormal(x, y, z, w float32) PackedNormal {
+	return PackedNormal(snorm(x, 10) | snorm(y, 10)<<10 | snorm(z, 10)<<20 | snorm(w, 2)<<30)
+}
+
+// PackNormals converts a slice of 3D normals (x, y, z triplets) to packed normals
+// The fourth component is set to 0
+func PackNormals(normals []float32) []PackedNormal {
+	if len(normals)%3 != 0 {
+		panic("PackNormals requires a slice of triplets")
+	}
+	res := make([]PackedNormal, len(normals)/3)
+	for i := range res {
+		res[i] = NewPackedNormal(normals[i*3], normals[i*3+1], normals[i*3+2], 0)
+	}
+	return res
+}
+
+// NewPackedFloat3 packs three non-negative floats in the 10F_11F_11F format
+func NewPackedFloat3(r, g, b float32) PackedFloat3 {
+	return PackedFloat3(packUFloat(r, 6) | packUFloat(g, 6)<<11 | packUFloat(b, 5)<<22)
+}
+
+// PackFloat3s converts a slice of triplets (e.g. HDR colors) to packed floats
+func PackFloat3s(data []float32) []PackedFloat3 {
+	if len(data)%3 != 0 {
+		panic("PackFloat3s requires a slice of triplets")
+	}
+	res := make([]PackedFloat3, len(data)/3)
+	for i := range res {
+		res[i] = NewPackedFloat3(data[i*3], data[i*3+1], data[i*3+2])
+	}
+	return res
+}
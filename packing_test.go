@@ -0,0 +1,117 @@
+package glad
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHalf(t *testing.T) {
+	inf := float32(math.Inf(1))
+	tests := []struct {
+		name string
+		f    float32
+		want Half
+		back float32 // Value converted back to float32
+	}{
+		{"Zero", 0, 0x0000, 0},
+		{"One", 1, 0x3c00, 1},
+		{"MinusTwo", -2, 0xc000, -2},
+		{"MaxFinite", 65504, 0x7bff, 65504},
+		{"RoundToMax", 65519, 0x7bff, 65504},
+		{"RoundToInf", 65520, 0x7c00, inf},
+		{"TooLarge", 1e10, 0x7c00, inf},
+		{"TooLargeNegative", -1e10, 0xfc00, -inf},
+		{"Inf", inf, 0x7c00, inf},
+		{"MinusInf", -inf, 0xfc00, -inf},
+		{"MinNormal", 1.0 / (1 << 14), 0x0400, 1.0 / (1 << 14)},
+		{"MinDenormal", 1.0 / (1 << 24), 0x0001, 1.0 / (1 << 24)},
+		{"MaxDenormal", 1023.0 / (1 << 24), 0x03ff, 1023.0 / (1 << 24)},
+		{"HalfMinDenormal", 1.0 / (1 << 25), 0x0000, 0}, // Ties to even
+		{"RoundUpDenormal", 3.0 / (1 << 26), 0x0001, 1.0 / (1 << 24)},
+		{"Underflow", 1e-10, 0x0000, 0},
+		{"NegativeUnderflow", -1e-10, 0x8000, float32(math.Copysign(0, -1))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHalf(tt.f)
+			if h != tt.want {
+				t.Fatalf("NewHalf(%v) = %#04x, want %#04x", tt.f, uint16(h), uint16(tt.want))
+			}
+			back := h.Float32()
+			if math.Float32bits(back) != math.Float32bits(tt.back) {
+				t.Errorf("%#04x.Float32() = %v, want %v", uint16(h), back, tt.back)
+			}
+		})
+	}
+
+	negZero := float32(math.Copysign(0, -1))
+	if h := NewHalf(negZero); h != 0x8000 || !math.Signbit(float64(h.Float32())) {
+		t.Errorf("NewHalf(-0) = %#04x, want the sign preserved", uint16(h))
+	}
+	nan := float32(math.NaN())
+	if h := NewHalf(nan); h&0x7c00 != 0x7c00 || h&0x3ff == 0 || !math.IsNaN(float64(h.Float32())) {
+		t.Errorf("NewHalf(NaN) = %#04x, want a NaN", uint16(h))
+	}
+}
+
+func TestPackedNormal(t *testing.T) {
+	inf := float32(math.Inf(1))
+	tests := []struct {
+		name       string
+		x, y, z, w float32
+		want       [4]uint32 // Fields, from the low bits
+	}{
+		{"Zero", 0, 0, 0, 0, [4]uint32{0, 0, 0, 0}},
+		{"NegativeZero", float32(math.Copysign(0, -1)), 0, 0, 0, [4]uint32{0, 0, 0, 0}},
+		{"Units", 1, -1, 0, 1, [4]uint32{0x1ff, 0x201, 0, 1}},
+		{"Half", 0.5, -0.5, 0, -1, [4]uint32{0x100, 0x300, 0, 3}},
+		{"ClampAbove", 2, 0, 1.5, 3, [4]uint32{0x1ff, 0, 0x1ff, 1}},
+		{"ClampBelow", -2, -1.5, 0, -3, [4]uint32{0x201, 0x201, 0, 3}},
+		{"ClampInf", inf, -inf, 0, 0, [4]uint32{0x1ff, 0x201, 0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := uint32(NewPackedNormal(tt.x, tt.y, tt.z, tt.w))
+			got := [4]uint32{p & 0x3ff, p >> 10 & 0x3ff, p >> 20 & 0x3ff, p >> 30}
+			if got != tt.want {
+				t.Errorf("got fields %#x, want %#x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPackedFloat3(t *testing.T) {
+	inf := float32(math.Inf(1))
+	tests := []struct {
+		name    string
+		r, g, b float32
+		want    [3]uint32 // Fields of 11, 11 and 10 bits, from the low bits
+	}{
+		{"Zero", 0, 0, 0, [3]uint32{0, 0, 0}},
+		{"NegativeZero", float32(math.Copysign(0, -1)), 0, 0, [3]uint32{0, 0, 0}},
+		{"One", 1, 1, 1, [3]uint32{0x3c0, 0x3c0, 0x1e0}},
+		{"Negative", -1, -inf, -1e-10, [3]uint32{0, 0, 0}},
+		{"MaxFinite", 65024, 65024, 64512, [3]uint32{0x7bf, 0x7bf, 0x3df}},
+		{"RoundToInf", 65536, 65024, 65100, [3]uint32{0x7c0, 0x7bf, 0x3e0}},
+		{"Inf", inf, inf, inf, [3]uint32{0x7c0, 0x7c0, 0x3e0}},
+		{"MinNormal", 1.0 / (1 << 14), 1.0 / (1 << 14), 1.0 / (1 << 14), [3]uint32{0x040, 0x040, 0x020}},
+		{"MinDenormal", 1.0 / (1 << 20), 1.0 / (1 << 20), 1.0 / (1 << 19), [3]uint32{1, 1, 1}},
+		{"MaxDenormal", 63.0 / (1 << 20), 63.0 / (1 << 20), 31.0 / (1 << 19), [3]uint32{0x3f, 0x3f, 0x1f}},
+		{"Underflow", 1e-10, 1e-10, 1e-10, [3]uint32{0, 0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := uint32(NewPackedFloat3(tt.r, tt.g, tt.b))
+			got := [3]uint32{p & 0x7ff, p >> 11 & 0x7ff, p >> 22}
+			if got != tt.want {
+				t.Errorf("got fields %#x, want %#x", got, tt.want)
+			}
+		})
+	}
+
+	nan := float32(math.NaN())
+	p := uint32(NewPackedFloat3(nan, nan, nan))
+	if r, b := p&0x7ff, p>>22; r&0x7c0 != 0x7c0 || r&0x3f == 0 || b&0x3e0 != 0x3e0 || b&0x1f == 0 {
+		t.Errorf("NewPackedFloat3(NaN) = %#x, want NaNs", p)
+	}
+}
@@ -0,0 +1,183 @@
+package glad
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+)
+
+// profilerLatency is the number of frames a section can wait for its GPU
+// timings before the queries are reused
+const profilerLatency = 4
+
+// Profiler measures the CPU and GPU time spent in named sections of a frame
+// GPU time is measured with timestamp queries, which are rotated so that
+// results are read a few frames later without stalling the pipeline.
+// Times are averaged over the last frames. Typical usage is
+//
+//	prof := glad.NewProfiler(60)
+//	for !win.ShouldClose() {
+//		end := prof.Section("scene")
+//		auto.AutoDraw()
+//		end()
+//		prof.Frame()
+//	}
+type Profiler struct {
+	frames   int
+	sections []*profSection
+	byName   map[string]*profSection
+}
+
+// SectionStats contains the average times of a section, in milliseconds
+type SectionStats struct {
+	Name string
+	CPU  float64
+	GPU  float64
+}
+
+type profSection struct {
+	name     string
+	queries  [profilerLatency][2]Query // Begin and end timestamps
+	pending  [profilerLatency]bool     // Queries waiting for results
+	slot     int                       // Queries to use next
+	cpuStart time.Time
+	cpu, gpu rollingMean
+}
+
+// rollingMean is the mean of the last len(samples) values added
+type rollingMean struct {
+	samples []float64
+	next    int
+	count   int
+}
+
+func (rm *rollingMean) add(v float64) {
+	rm.samples[rm.next] = v
+	rm.next = (rm.next + 1) % len(rm.samples)
+	if rm.count < len(rm.samples) {
+		rm.count++
+	}
+}
+
+func (rm *rollingMean) mean() float64 {
+	if rm.count == 0 {
+		return 0
+	}
+	var sum float64
+	for i := 0; i < rm.count; i++ {
+		sum += rm.samples[i]
+	}
+	return sum / float64(rm.count)
+}
+
+// NewProfiler creates a profiler averaging times over the given number of frames
+func NewProfiler(frames int) *Profiler {
+	if frames < 1 {
+		frames = 1
+	}
+	return &Profiler{frames: frames, byName: make(map[string]*profSection)}
+}
+
+// section returns the named section, creating it if needed
+func (p *Profiler) section(name string) *profSection {
+	if s, ok := p.byName[name]; ok {
+		return s
+	}
+	s := &profSection{
+		name: name,
+		cpu:  rollingMean{samples: make([]float64, p.frames)},
+		gpu:  rollingMean{samples: make([]float64, p.frames)},
+	}
+	for i := range s.queries {
+		s.queries[i][0] = NewQuery(gl.TIMESTAMP)
+		s.queries[i][1] = NewQuery(gl.TIMESTAMP)
+	}
+	p.sections = append(p.sections, s)
+	p.byName[name] = s
+	return s
+}
+
+// Begin starts measuring the named section
+func (p *Profiler) Begin(name string) {
+	s := p.section(name)
+	if s.pending[s.slot] {
+		// If the result is still not there, the GPU timing is lost
+		s.collect(s.slot)
+		s.pending[s.slot] = false
+	}
+	s.cpuStart = time.Now()
+	s.queries[s.slot][0].Timestamp()
+}
+
+// End stops measuring the named section
+func (p *Profiler) End(name string) {
+	s := p.section(name)
+	s.queries[s.slot][1].Timestamp()
+	s.pending[s.slot] = true
+	s.slot = (s.slot + 1) % profilerLatency
+	s.cpu.add(float64(time.Since(s.cpuStart)) / float64(time.Millisecond))
+}
+
+// Section begins the named section and returns a function to end it
+func (p *Profiler) Section(name string) func() {
+	p.Begin(name)
+	return func() {
+		p.End(name)
+	}
+}
+
+// Frame collects the GPU timings that are ready, call it once per frame
+func (p *Profiler) Frame() {
+	for _, s := range p.sections {
+		for i := range s.pending {
+			if s.pending[i] {
+				s.collect(i)
+			}
+		}
+	}
+}
+
+// collect reads the GPU timing of the slot if available
+func (s *profSection) collect(slot int) {
+	end, ok := s.queries[slot][1].TryResult()
+	if !ok {
+		return
+	}
+	// Queries complete in order, so the begin timestamp is available too
+	start := s.queries[slot][0].Result()
+	s.gpu.add(float64(end-start) / float64(time.Millisecond))
+	s.pending[slot] = false
+}
+
+// Stats returns the average times of each section, in the order in which
+// sections were first measured
+func (p *Profiler) Stats() []SectionStats {
+	stats := make([]SectionStats, len(p.sections))
+	for i, s := range p.sections {
+		stats[i] = SectionStats{s.name, s.cpu.mean(), s.gpu.mean()}
+	}
+	return stats
+}
+
+// String formats the stats of all the sections, one per line
+func (p *Profiler) String() string {
+	var sb strings.Builder
+	for _, st := range p.Stats() {
+		fmt.Fprintf(&sb, "%s: CPU %.3f ms, GPU %.3f ms\n", st.Name, st.CPU, st.GPU)
+	}
+	return sb.String()
+}
+
+// Delete frees the queries used by the profiler
+func (p *Profiler) Delete() {
+	for _, s := range p.sections {
+		for i := range s.queries {
+			s.queries[i][0].Delete()
+			s.queries[i][1].Delete()
+		}
+	}
+	p.sections = nil
+	p.byName = make(map[string]*profSection)
+}
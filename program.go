@@ -1,7 +1,9 @@
 package glad
 
 import (
+	"fmt"
 	"log"
+	"strings"
 
 	"github.com/go-gl/gl/v4.5-core/gl"
 )
@@ -19,34 +21,72 @@ func PrepareProgram(shaders ...Shader) Program {
 
 // NewProgram creates a program with a new name
 func NewProgram() Program {
-	return Program(gl.CreateProgram())
+	checkThread()
+	pr := Program(gl.CreateProgram())
+	registry.track("Program", uint32(pr))
+	return pr
 }
 
 // AttachShaders attaches one or more shaders to the program
 func (pr Program) AttachShaders(shaders ...Shader) {
+	checkThread()
 	for _, sh := range shaders {
 		gl.AttachShader(uint32(pr), uint32(sh))
 	}
 }
 
+// LinkProgram creates a program with the shaders and links it, returning
+// an error with the info log if link did not succeed. The shaders are
+// detached afterwards, so they can be deleted
+func LinkProgram(shaders ...Shader) (Program, error) {
+	pr := NewProgram()
+	pr.AttachShaders(shaders...)
+	err := pr.TryLink()
+	pr.DetachShaders(shaders...)
+	if err != nil {
+		pr.Delete()
+		return 0, err
+	}
+	return pr, nil
+}
+
+// DetachShaders detaches one or more shaders from the program
+func (pr Program) DetachShaders(shaders ...Shader) {
+	checkThread()
+	for _, sh := range shaders {
+		gl.DetachShader(uint32(pr), uint32(sh))
+	}
+}
+
 // Link links the attached shaders belonging to the program
 // logging an error if link did not succeed
 func (pr Program) Link() {
+	if err := pr.TryLink(); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// TryLink is like Link, but returns an error with the info log if link did
+// not succeed, instead of exiting
+func (pr Program) TryLink() error {
+	checkThread()
 	gl.LinkProgram(uint32(pr))
 
 	if pr.GetParameter(gl.LINK_STATUS) == gl.FALSE {
-		infoLog := pr.GetInfoLog()
-		log.Fatalln("Unable to link program:\n", infoLog)
+		return fmt.Errorf("unable to link program:\n%s", strings.TrimRight(pr.GetInfoLog(), "\x00"))
 	}
+	return nil
 }
 
 func (pr Program) GetParameter(pname uint32) int32 {
+	checkThread()
 	var val int32
 	gl.GetProgramiv(uint32(pr), pname, &val)
 	return val
 }
 
 func (pr Program) GetInfoLog() string {
+	checkThread()
 	logLen := pr.GetParameter(gl.INFO_LOG_LENGTH)
 	infoLog := string(make([]byte, int(logLen+1)))
 	var savedLen int32
@@ -59,19 +99,54 @@ func (pr Program) GetInfoLog() string {
 
 // Call this before linking to set location of attributes
 func (pr Program) BindAttributeLocation(index uint32, name string) {
+	checkThread()
 	cname := gl.Str(name + "\x00")
 	gl.BindAttribLocation(uint32(pr), index, cname)
 }
 
 // Call this after linking to get location of attributes (e.g. if they were not set)
 func (pr Program) GetAttributeLocation(name string) VertexAttrib {
+	checkThread()
 	return VertexAttrib(gl.GetAttribLocation(uint32(pr), gl.Str(name+"\x00")))
 }
 
+// ActiveAttrib describes an attribute used by a linked program
+type ActiveAttrib struct {
+	Name     string
+	Type     uint32 // e.g. gl.FLOAT_VEC3
+	Size     int32  // Number of elements, for arrays
+	Location VertexAttrib
+}
+
+// GetActiveAttributes returns the attributes used by the linked program
+func (pr Program) GetActiveAttributes() []ActiveAttrib {
+	checkThread()
+	n := pr.GetParameter(gl.ACTIVE_ATTRIBUTES)
+	maxLen := pr.GetParameter(gl.ACTIVE_ATTRIBUTE_MAX_LENGTH)
+	attrs := make([]ActiveAttrib, n)
+	for i := range attrs {
+		var length, size int32
+		var typ uint32
+		name := make([]uint8, maxLen+1)
+		gl.GetActiveAttrib(uint32(pr), uint32(i), maxLen+1, &length, &size, &typ, &name[0])
+		attrs[i].Name = string(name[:length])
+		attrs[i].Type = typ
+		attrs[i].Size = size
+		attrs[i].Location = pr.GetAttributeLocation(attrs[i].Name)
+	}
+	return attrs
+}
+
 func (pr Program) Use() {
-	gl.UseProgram(uint32(pr))
+	checkThread()
+	if stateCache().bind(bindProgram, 0, uint32(pr)) {
+		gl.UseProgram(uint32(pr))
+	}
 }
 
 func (pr Program) Delete() {
+	checkThread()
 	gl.DeleteProgram(uint32(pr))
+	stateCache().forget(bindProgram, uint32(pr))
+	registry.untrack("Program", uint32(pr))
 }
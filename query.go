@@ -0,0 +1,82 @@
+package glad
+
+import "github.com/go-gl/gl/v4.5-core/gl"
+
+// Query represents a query object in the OpenGL context
+// Queries are used to ask the GL server for information about the commands
+// it executed, e.g. how much time they took or how many samples passed the
+// depth test. Results are available asynchronously: poll them with
+// TryResult to avoid stalling the pipeline
+// Target can be:
+// - gl.TIME_ELAPSED the time in nanoseconds spent by the GPU between Begin and End
+// - gl.TIMESTAMP the GPU time in nanoseconds when Timestamp is executed
+// - gl.SAMPLES_PASSED the number of samples passing the depth test
+// - gl.ANY_SAMPLES_PASSED 1 if any sample passed the depth test, 0 otherwise
+// - gl.PRIMITIVES_GENERATED the number of primitives emitted by the geometry stage
+type Query struct {
+	Name   uint32
+	Target uint32
+}
+
+// NewQuery creates a new query object for the target
+func NewQuery(target uint32) Query {
+	checkThread()
+	var q uint32
+	gl.CreateQueries(target, 1, &q)
+	registry.track("Query", q)
+	return Query{q, target}
+}
+
+// Delete the query freeing its name
+func (q Query) Delete() {
+	checkThread()
+	gl.DeleteQueries(1, &q.Name)
+	registry.untrack("Query", q.Name)
+}
+
+// Begin starts counting for the query
+// Only one query per target can be active at the same time
+func (q Query) Begin() {
+	checkThread()
+	gl.BeginQuery(q.Target, q.Name)
+}
+
+// End stops counting for the query: the result will be available later
+func (q Query) End() {
+	checkThread()
+	gl.EndQuery(q.Target)
+}
+
+// Timestamp records the GPU time when all the previous commands are completed
+// This is valid only for gl.TIMESTAMP queries, that do not use Begin and End
+func (q Query) Timestamp() {
+	checkThread()
+	gl.QueryCounter(q.Name, gl.TIMESTAMP)
+}
+
+// Available returns true if the result of the query is ready
+func (q Query) Available() bool {
+	checkThread()
+	var av uint32
+	gl.GetQueryObjectuiv(q.Name, gl.QUERY_RESULT_AVAILABLE, &av)
+	return av == gl.TRUE
+}
+
+// Result returns the result of the query, waiting until it is available
+func (q Query) Result() uint64 {
+	checkThread()
+	var res uint64
+	gl.GetQueryObjectui64v(q.Name, gl.QUERY_RESULT, &res)
+	return res
+}
+
+// TryResult returns the result of the query without waiting
+// ok is false if the result is not yet available
+func (q Query) TryResult() (res uint64, ok bool) {
+	checkThread()
+	if !q.Available() {
+		return 0, false
+	}
+	gl.GetQueryObjectui64v(q.Name, gl.QUERY_RESULT_NO_WAIT, &res)
+	return res, true
+}
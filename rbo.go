@@ -13,29 +13,49 @@ type RBOParameter int32
 
 // NewRenderbuffer creates a new renderbuffer object
 func NewRenderbuffer() RenderbufferObject {
+	checkThread()
 	var rbo uint32
 	gl.CreateRenderbuffers(1, &rbo)
+	registry.track("RenderbufferObject", rbo)
 	return RenderbufferObject(rbo)
 }
 
+// Delete the RBO freeing its name
+func (rbo RenderbufferObject) Delete() {
+	checkThread()
+	r := uint32(rbo)
+	gl.DeleteRenderbuffers(1, &r)
+	stateCache().forget(bindRenderbuffer, r)
+	registry.untrack("RenderbufferObject", r)
+}
+
 // Bind the RBO to the renderbuffer target
 func (rbo RenderbufferObject) Bind() {
-	gl.BindRenderbuffer(gl.RENDERBUFFER, uint32(rbo))
+	checkThread()
+	if stateCache().bind(bindRenderbuffer, 0, uint32(rbo)) {
+		gl.BindRenderbuffer(gl.RENDERBUFFER, uint32(rbo))
+	}
 }
 
 // Unbind the RBO from the renderbuffer target
 func (rbo RenderbufferObject) Unbind() {
-	gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+	checkThread()
+	if stateCache().bind(bindRenderbuffer, 0, 0) {
+		gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+	}
 }
 
 // Storage allocates the storage for the RBO
 // format can be gl.RGB, RGBA, etc, gl.STENCIL_INDEX or gl.DEPTH_COMPONENT
 func (rbo RenderbufferObject) Storage(format uint32, width, height int32) {
+	checkThread()
 	gl.NamedRenderbufferStorage(uint32(rbo), format, width, height)
+	registry.resize("RenderbufferObject", uint32(rbo), imageSize(1, format, []int{int(width), int(height)}))
 }
 
 // GetParameter returns the RBO parameter value
 func (rbo RenderbufferObject) GetParameter(param uint32) int32 {
+	checkThread()
 	var v int32
 	gl.GetNamedRenderbufferParameteriv(uint32(rbo), param, &v)
 	return v
@@ -0,0 +1,184 @@
+package glad
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+)
+
+// ResourceRegistry keeps track of the OpenGL objects created by this package
+// and not yet deleted, to find leaks. Use UseResourceRegistry to start
+// tracking: every object created through the wrappers (NewTexture,
+// NewVertexBufferObject, NewProgram, etc) is recorded with the stack of the
+// creation, and removed from the registry when deleted
+type ResourceRegistry struct {
+	mu    sync.Mutex // Objects can be created by a Loader on another thread
+	alive map[resourceKey]*Resource
+}
+
+// Resource describes an object tracked by the registry
+type Resource struct {
+	Type  string // Type of the object, e.g. "Texture"
+	Name  uint32 // Name of the object in the context
+	Size  int    // Estimated size in bytes of the storage, 0 if unknown
+	Stack string // Call stack where the object was created
+}
+
+type resourceKey struct {
+	typ  string
+	name uint32
+}
+
+// registry is the registry in use, nil if tracking is disabled
+var registry *ResourceRegistry
+
+// NewResourceRegistry creates an empty registry
+func NewResourceRegistry() *ResourceRegistry {
+	return &ResourceRegistry{alive: make(map[resourceKey]*Resource)}
+}
+
+// UseResourceRegistry sets the registry where objects will be recorded
+// Objects created before calling this are not tracked. Pass nil to stop tracking
+func UseResourceRegistry(reg *ResourceRegistry) {
+	registry = reg
+}
+
+// CurrentResourceRegistry returns the registry in use, nil if none
+func CurrentResourceRegistry() *ResourceRegistry {
+	return registry
+}
+
+// Alive returns the objects that were created and not deleted yet,
+// sorted by type and name
+func (reg *ResourceRegistry) Alive() []Resource {
+	reg.mu.Lock()
+	res := make([]Resource, 0, len(reg.alive))
+	for _, r := range reg.alive {
+		res = append(res, *r)
+	}
+	reg.mu.Unlock()
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Type != res[j].Type {
+			return res[i].Type < res[j].Type
+		}
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+// Report returns a description of the objects still alive, with their
+// creation stack. Call it at shutdown or at the end of a test, after deleting
+// everything: any object listed was leaked. An empty string means no leaks
+func (reg *ResourceRegistry) Report() string {
+	alive := reg.Alive()
+	if len(alive) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	total := 0
+	for _, r := range alive {
+		total += r.Size
+	}
+	fmt.Fprintf(&sb, "%d leaked objects (%d bytes estimated)\n", len(alive), total)
+	for _, r := range alive {
+		fmt.Fprintf(&sb, "%s %d (%d bytes) created at:\n%s", r.Type, r.Name, r.Size, r.Stack)
+	}
+	return sb.String()
+}
+
+// track records a new object, it is safe to call with a nil registry
+func (reg *ResourceRegistry) track(typ string, name uint32) {
+	if reg == nil {
+		return
+	}
+	r := &Resource{
+		Type:  typ,
+		Name:  name,
+		Stack: callerStack(3),
+	}
+	reg.mu.Lock()
+	reg.alive[resourceKey{typ, name}] = r
+	reg.mu.Unlock()
+}
+
+// untrack removes a deleted object from the registry
+func (reg *ResourceRegistry) untrack(typ string, name uint32) {
+	if reg == nil {
+		return
+	}
+	reg.mu.Lock()
+	delete(reg.alive, resourceKey{typ, name})
+	reg.mu.Unlock()
+}
+
+// resize updates the estimated size of an object after (re)allocating storage
+func (reg *ResourceRegistry) resize(typ string, name uint32, size int) {
+	if reg == nil {
+		return
+	}
+	reg.mu.Lock()
+	if r, ok := reg.alive[resourceKey{typ, name}]; ok {
+		r.Size = size
+	}
+	reg.mu.Unlock()
+}
+
+// callerStack formats the call stack, skipping the innermost frames
+func callerStack(skip int) string {
+	pc := make([]uintptr, 32)
+	n := runtime.Callers(skip+1, pc)
+	frames := runtime.CallersFrames(pc[:n])
+	var sb strings.Builder
+	for {
+		f, more := frames.Next()
+		fmt.Fprintf(&sb, "\t%s\n\t\t%s:%d\n", f.Function, f.File, f.Line)
+		if !more {
+			break
+		}
+	}
+	return sb.String()
+}
+
+// texelSize estimates the bytes used by a texel of the given internal format
+func texelSize(internalFmt uint32) int {
+	switch internalFmt {
+	case gl.R8, gl.R8I, gl.R8UI, gl.STENCIL_INDEX8:
+		return 1
+	case gl.RG8, gl.R16, gl.R16F, gl.R16I, gl.R16UI, gl.DEPTH_COMPONENT16:
+		return 2
+	case gl.RGB8, gl.SRGB8, gl.DEPTH_COMPONENT24:
+		return 3
+	case gl.RGB16F, gl.RGB16:
+		return 6
+	case gl.RGBA16F, gl.RGBA16, gl.RG32F, gl.RG32I, gl.RG32UI, gl.DEPTH32F_STENCIL8:
+		return 8
+	case gl.RGB32F, gl.RGB32I, gl.RGB32UI:
+		return 12
+	case gl.RGBA32F, gl.RGBA32I, gl.RGBA32UI:
+		return 16
+	default:
+		// Most formats (RGBA8, R32F, DEPTH24_STENCIL8, etc) use 4 bytes
+		return 4
+	}
+}
+
+// imageSize estimates the bytes used by levels mipmaps of an image
+func imageSize(levels int32, internalFmt uint32, size []int) int {
+	total := 0
+	for l := int32(0); l < levels; l++ {
+		n := texelSize(internalFmt)
+		for _, s := range size {
+			s >>= uint(l)
+			if s < 1 {
+				s = 1
+			}
+			n *= s
+		}
+		total += n
+	}
+	return total
+}
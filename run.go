@@ -0,0 +1,205 @@
+package glad
+
+import (
+	"math"
+	"time"
+)
+
+// App is an application driven by Run
+// The simulation advances in Update with a fixed time step, decoupled from
+// the frame rate, while Render draws as often as possible and receives how
+// far the simulation is between the last step and the next one, to
+// interpolate the state
+type App interface {
+	// Init is called once, with the context current, before the first frame
+	Init(l *Loop) error
+	// Update advances the simulation by dt seconds, always the fixed step
+	Update(dt float64)
+	// Render draws a frame, alpha in [0, 1) is the fraction of step elapsed
+	// since the last Update
+	Render(alpha float64)
+	// Resize is called with the framebuffer size in pixels, before the
+	// first frame and whenever it changes
+	Resize(width, height int)
+	// Close is called once when the loop ends, with the context current
+	Close()
+}
+
+// FrameStats contains the frame statistics averaged over the last frames
+type FrameStats struct {
+	FPS       float64       // Frames per second
+	FrameTime time.Duration // Average time between frames
+	MinFrame  time.Duration // Shortest frame
+	MaxFrame  time.Duration // Longest frame
+	Frames    uint64        // Frames rendered since the start
+	Updates   uint64        // Simulation steps since the start
+}
+
+// Loop is the state of Run, passed to the application in Init
+type Loop struct {
+	Window *Window
+
+	step       time.Duration // Fixed simulation step
+	maxFPS     float64       // 0 for no cap
+	maxUpdates int           // Steps per frame before dropping time
+	frameTimes rollingMean   // In seconds
+	frames     uint64
+	updates    uint64
+	simTime    float64
+	quit       bool
+}
+
+// RunOption sets an option of Run
+type RunOption func(*Loop)
+
+// FixedStep sets the simulation step, by default 1/60 of second
+func FixedStep(step time.Duration) RunOption {
+	return func(l *Loop) {
+		if step > 0 {
+			l.step = step
+		}
+	}
+}
+
+// MaxFPS caps the frame rate by sleeping after each frame, 0 to disable
+// It can be combined with VSync, the lower rate wins
+func MaxFPS(fps float64) RunOption {
+	return func(l *Loop) { l.maxFPS = fps }
+}
+
+// MaxUpdates limits the simulation steps done in a single frame (default 8)
+// When the simulation can't keep up, time is dropped and it slows down
+// instead of spiraling into longer and longer frames
+func MaxUpdates(n int) RunOption {
+	return func(l *Loop) {
+		if n > 0 {
+			l.maxUpdates = n
+		}
+	}
+}
+
+// StatsFrames sets the number of frames averaged in the stats (default 60)
+func StatsFrames(n int) RunOption {
+	return func(l *Loop) {
+		if n > 0 {
+			l.frameTimes.samples = make([]float64, n)
+		}
+	}
+}
+
+// Run drives the application until the window is closed or Quit is called
+// It must be called from the thread of the context, the window is not
+// destroyed when it returns. A typical main is
+//
+//	win, err := glad.NewWindow(800, 600, "App", glad.VSync(true))
+//	if err != nil {
+//		log.Fatalln(err)
+//	}
+//	defer glad.Terminate()
+//	if err := glad.Run(win, &myApp{}, glad.FixedStep(time.Second/120)); err != nil {
+//		log.Fatalln(err)
+//	}
+func Run(win *Window, app App, opts ...RunOption) error {
+	l := &Loop{
+		Window:     win,
+		step:       time.Second / 60,
+		maxUpdates: 8,
+		frameTimes: rollingMean{samples: make([]float64, 60)},
+	}
+	for _, o := range opts {
+		o(l)
+	}
+
+	win.MakeCurrent()
+	if err := app.Init(l); err != nil {
+		return err
+	}
+	defer app.Close()
+
+	fbW, fbH := win.FramebufferSize()
+	app.Resize(fbW, fbH)
+
+	step := l.step.Seconds()
+	var acc float64
+	last := time.Now()
+	for !l.quit && !win.ShouldClose() {
+		now := time.Now()
+		elapsed := now.Sub(last)
+		last = now
+		if l.frames > 0 {
+			l.frameTimes.add(elapsed.Seconds())
+		}
+
+		acc += elapsed.Seconds()
+		n := 0
+		for acc >= step && !l.quit {
+			if n == l.maxUpdates {
+				acc = math.Mod(acc, step)
+				break
+			}
+			app.Update(step)
+			acc -= step
+			l.simTime += step
+			l.updates++
+			n++
+		}
+
+		app.Render(acc / step)
+		l.frames++
+		win.Swap()
+		win.PollEvents()
+
+		if w, h := win.FramebufferSize(); w != fbW || h != fbH {
+			fbW, fbH = w, h
+			app.Resize(w, h)
+		}
+
+		if l.maxFPS > 0 {
+			frame := time.Duration(float64(time.Second) / l.maxFPS)
+			if d := frame - time.Since(now); d > 0 {
+				time.Sleep(d)
+			}
+		}
+	}
+	return nil
+}
+
+// Quit ends the loop after the current frame
+func (l *Loop) Quit() {
+	l.quit = true
+}
+
+// Step returns the fixed simulation step
+func (l *Loop) Step() time.Duration {
+	return l.step
+}
+
+// Time returns the simulated time in seconds, advanced by each Update
+func (l *Loop) Time() float64 {
+	return l.simTime
+}
+
+// Stats returns the frame statistics
+func (l *Loop) Stats() FrameStats {
+	s := FrameStats{Frames: l.frames, Updates: l.updates}
+	rm := &l.frameTimes
+	if rm.count == 0 {
+		return s
+	}
+	mean := rm.mean()
+	s.FrameTime = seconds(mean)
+	if mean > 0 {
+		s.FPS = 1 / mean
+	}
+	min, max := rm.samples[0], rm.samples[0]
+	for _, v := range rm.samples[:rm.count] {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+	s.MinFrame, s.MaxFrame = seconds(min), seconds(max)
+	return s
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,59 @@
+package glad
+
+import "github.com/go-gl/gl/v4.5-core/gl"
+
+// Sampler represents a sampler object in the OpenGL context
+// A sampler holds the parameters used to read from a texture (filters,
+// wrapping, etc). When bound to a texture unit, it overrides the parameters
+// of the texture bound to the same unit
+type Sampler uint32
+
+// NewSampler creates a new sampler object with default parameters
+func NewSampler() Sampler {
+	checkThread()
+	var smp uint32
+	gl.CreateSamplers(1, &smp)
+	registry.track("Sampler", smp)
+	return Sampler(smp)
+}
+
+// Delete the sampler freeing its name
+func (smp Sampler) Delete() {
+	checkThread()
+	s := uint32(smp)
+	gl.DeleteSamplers(1, &s)
+	stateCache().forget(bindSampler, s)
+	registry.untrack("Sampler", s)
+}
+
+// Bind the sampler to the specified texture unit
+func (smp Sampler) Bind(unit uint32) {
+	checkThread()
+	if stateCache().bind(bindSampler, unit, uint32(smp)) {
+		gl.BindSampler(unit, uint32(smp))
+	}
+}
+
+// Unbind the sampler from the texture unit
+func (smp Sampler) Unbind(unit uint32) {
+	checkThread()
+	if stateCache().bind(bindSampler, unit, 0) {
+		gl.BindSampler(unit, 0)
+	}
+}
+
+// SetFilters sets the magnification and minification filters
+func (smp Sampler) SetFilters(magFilter, minFilter int32) {
+	checkThread()
+	gl.SamplerParameteri(uint32(smp), gl.TEXTURE_MAG_FILTER, magFilter)
+	gl.SamplerParameteri(uint32(smp), gl.TEXTURE_MIN_FILTER, minFilter)
+}
+
+// SetWrap sets the wrapping mode for the S, T and R coordinates
+// mode can be gl.REPEAT, gl.CLAMP_TO_EDGE, gl.MIRRORED_REPEAT, etc
+func (smp Sampler) SetWrap(s, t, r int32) {
+	checkThread()
+	gl.SamplerParameteri(uint32(smp), gl.TEXTURE_WRAP_S, s)
+	gl.SamplerParameteri(uint32(smp), gl.TEXTURE_WRAP_T, t)
+	gl.SamplerParameteri(uint32(smp), gl.TEXTURE_WRAP_R, r)
+}
@@ -1,7 +1,9 @@
 package glad
 
 import (
+	"errors"
 	"log"
+	"strings"
 
 	"github.com/go-gl/gl/v4.5-core/gl"
 )
@@ -9,37 +11,68 @@ import (
 // Shader represents a shader in the OpenGL context
 type Shader uint32
 
+// ShaderError is returned when a shader does not compile
+type ShaderError struct {
+	File  string // Source file, if known
+	Stage uint32 // e.g. gl.VERTEX_SHADER
+	Log   string // Info log of the compiler
+}
+
+func (e *ShaderError) Error() string {
+	if e.File == "" {
+		return "unable to compile shader:\n" + e.Log
+	}
+	return e.File + ": " + e.Log
+}
+
 // NewShader compiles the shader source and returns a shader object
 // errors are logged. Source code does not need to end with \x00
 func NewShader(source string, shaderType uint32) Shader {
+	sh, err := CompileShader(source, shaderType)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	return sh
+}
+
+// CompileShader is like NewShader, but returns an error with the info log if
+// the compilation fails, instead of exiting
+func CompileShader(source string, shaderType uint32) (Shader, error) {
+	checkThread()
 	if source == "" {
-		log.Fatalln("Unable to create shader from empty string")
+		return 0, errors.New("unable to create shader from empty string")
 	}
 	var sh Shader
 	sh = Shader(gl.CreateShader(shaderType))
+	registry.track("Shader", uint32(sh))
 	csrc, free := gl.Strs(source + "\x00")
 	gl.ShaderSource(uint32(sh), 1, csrc, nil)
 	free()
 	gl.CompileShader(uint32(sh))
 
 	if sh.GetParameter(gl.COMPILE_STATUS) == gl.FALSE {
-		infoLog := sh.GetInfoLog()
-		log.Fatalln("Unable to compile shader:\n", infoLog)
+		infoLog := strings.TrimRight(sh.GetInfoLog(), "\x00")
+		sh.Delete()
+		return 0, &ShaderError{Stage: shaderType, Log: infoLog}
 	}
-	return sh
+	return sh, nil
 }
 
 func (sh Shader) Delete() {
+	checkThread()
 	gl.DeleteShader(uint32(sh))
+	registry.untrack("Shader", uint32(sh))
 }
 
 func (sh Shader) GetParameter(pname uint32) int32 {
+	checkThread()
 	var val int32
 	gl.GetShaderiv(uint32(sh), pname, &val)
 	return val
 }
 
 func (sh Shader) GetInfoLog() string {
+	checkThread()
 	logLen := sh.GetParameter(gl.INFO_LOG_LENGTH)
 	infoLog := string(make([]byte, int(logLen+1)))
 	var savedLen int32
@@ -0,0 +1,250 @@
+package glad
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/akiross/go-glad/glsl"
+	"github.com/go-gl/gl/v4.5-core/gl"
+)
+
+// ShaderLibrary loads programs from shader files and reloads them when the
+// files change, to edit shaders while the application is running.
+// Files are read from a fs.FS (use NewShaderLibraryDir for a directory on
+// disk) and passed through the glsl preprocessor, so they can include other
+// files. All the files are watched by polling their modification time, which
+// works on any system. A program is relinked in place, so the Program
+// returned by Load remains valid; if a shader does not compile or link, the
+// last working program is kept and the error is reported to OnError.
+// Typical usage is
+//
+//	lib := glad.NewShaderLibraryDir("shaders")
+//	prog, err := lib.Load("sprite", "sprite.vert", "sprite.frag")
+//	for !win.ShouldClose() {
+//		lib.Poll()
+//		prog.Use()
+//		// ... draw ...
+//	}
+//
+// Uniform values and locations can change when a program is relinked, so
+// they should be set every frame, or again when Poll returns true
+type ShaderLibrary struct {
+	// OnError is called when a reload fails, by default the error is logged
+	OnError func(program string, err error)
+	// Interval is the minimum time between two checks of the files
+	Interval time.Duration
+	// Options of the preprocessor, used to compile all the files
+	Options *glsl.Options
+
+	fsys      fs.FS
+	programs  map[string]*libProgram
+	names     []string // Programs in load order
+	lastCheck time.Time
+}
+
+// libProgram is a program of the library and the files it is made of
+type libProgram struct {
+	prog  Program
+	files []string
+	mtime map[string]time.Time // Modification time when the file was read
+}
+
+// NewShaderLibrary creates a library reading the files from fsys
+func NewShaderLibrary(fsys fs.FS) *ShaderLibrary {
+	return &ShaderLibrary{
+		OnError: func(program string, err error) {
+			log.Printf("Shader library: reloading %s: %v", program, err)
+		},
+		Interval: 250 * time.Millisecond,
+		fsys:     fsys,
+		programs: make(map[string]*libProgram),
+	}
+}
+
+// NewShaderLibraryDir creates a library reading the files in a directory
+func NewShaderLibraryDir(dir string) *ShaderLibrary {
+	return NewShaderLibrary(os.DirFS(dir))
+}
+
+// shaderStages maps the extensions of shader files to their stage
+var shaderStages = map[string]uint32{
+	".vert": gl.VERTEX_SHADER,
+	".vs":   gl.VERTEX_SHADER,
+	".frag": gl.FRAGMENT_SHADER,
+	".fs":   gl.FRAGMENT_SHADER,
+	".geom": gl.GEOMETRY_SHADER,
+	".gs":   gl.GEOMETRY_SHADER,
+	".tesc": gl.TESS_CONTROL_SHADER,
+	".tese": gl.TESS_EVALUATION_SHADER,
+	".comp": gl.COMPUTE_SHADER,
+	".cs":   gl.COMPUTE_SHADER,
+}
+
+// ShaderStage returns the stage of a shader file from its extension, e.g.
+// gl.FRAGMENT_SHADER for "light.frag" or "light.frag.glsl"
+func ShaderStage(file string) (uint32, bool) {
+	ext := path.Ext(strings.TrimSuffix(file, ".glsl"))
+	stage, ok := shaderStages[ext]
+	return stage, ok
+}
+
+// Load creates a program with the given name from shader files, whose stage
+// is given by the extension (see ShaderStage). Loading an existing name
+// replaces the files of the program and rebuilds it in place
+func (lib *ShaderLibrary) Load(name string, files ...string) (Program, error) {
+	if p, ok := lib.programs[name]; ok {
+		oldFiles, oldMtime := p.files, p.mtime
+		p.files = append([]string(nil), files...)
+		if err := lib.rebuild(p); err != nil {
+			p.files, p.mtime = oldFiles, oldMtime
+			return 0, err
+		}
+		return p.prog, nil
+	}
+	p := &libProgram{files: append([]string(nil), files...)}
+	shaders, err := lib.compile(p)
+	if err != nil {
+		return 0, err
+	}
+	p.prog, err = LinkProgram(shaders...)
+	deleteShaders(shaders)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %v", name, err)
+	}
+	p.prog.SetLabel(name)
+	lib.programs[name] = p
+	lib.names = append(lib.names, name)
+	return p.prog, nil
+}
+
+// Program returns the program with the given name, 0 if not loaded
+func (lib *ShaderLibrary) Program(name string) Program {
+	if p, ok := lib.programs[name]; ok {
+		return p.prog
+	}
+	return 0
+}
+
+// Poll checks if the files changed and rebuilds the affected programs,
+// returning true if any program was relinked. Files are checked at most once
+// every Interval, so it can be called every frame
+func (lib *ShaderLibrary) Poll() bool {
+	if time.Since(lib.lastCheck) < lib.Interval {
+		return false
+	}
+	lib.lastCheck = time.Now()
+	changed := false
+	for _, name := range lib.names {
+		p := lib.programs[name]
+		if !lib.modified(p) {
+			continue
+		}
+		if err := lib.rebuild(p); err != nil {
+			lib.OnError(name, err)
+			continue
+		}
+		changed = true
+	}
+	return changed
+}
+
+// Reload rebuilds the program now, even if its files did not change
+func (lib *ShaderLibrary) Reload(name string) error {
+	p, ok := lib.programs[name]
+	if !ok {
+		return fmt.Errorf("shader library: no program %q", name)
+	}
+	return lib.rebuild(p)
+}
+
+// Delete deletes all the programs of the library
+func (lib *ShaderLibrary) Delete() {
+	for _, name := range lib.names {
+		lib.programs[name].prog.Delete()
+	}
+	lib.programs = make(map[string]*libProgram)
+	lib.names = nil
+}
+
+// modified reports if a file of the program changed since it was read
+// Files that can't be accessed (e.g. while an editor saves them) are ignored
+func (lib *ShaderLibrary) modified(p *libProgram) bool {
+	for file, t := range p.mtime {
+		if fi, err := fs.Stat(lib.fsys, file); err == nil && !fi.ModTime().Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// rebuild compiles the files of the program and relinks it in place
+// The new shaders are linked in a temporary program first: the program is
+// modified only if that succeeds, so it keeps working after an error
+func (lib *ShaderLibrary) rebuild(p *libProgram) error {
+	shaders, err := lib.compile(p)
+	if err != nil {
+		return err
+	}
+	defer deleteShaders(shaders)
+	test, err := LinkProgram(shaders...)
+	if err != nil {
+		return err
+	}
+	test.Delete()
+	p.prog.AttachShaders(shaders...)
+	err = p.prog.TryLink()
+	p.prog.DetachShaders(shaders...)
+	return err
+}
+
+// compile reads and compiles the files of the program, recording the
+// modification time of them and their includes even on errors, so that a
+// broken file is not reloaded until it changes again
+func (lib *ShaderLibrary) compile(p *libProgram) ([]Shader, error) {
+	p.mtime = make(map[string]time.Time, len(p.files))
+	var shaders []Shader
+	for _, file := range p.files {
+		stage, ok := ShaderStage(file)
+		if !ok {
+			deleteShaders(shaders)
+			return nil, fmt.Errorf("%s: unknown shader stage", file)
+		}
+		src, err := glsl.Preprocess(lib.fsys, file, lib.Options)
+		for _, f := range src.Files {
+			lib.stat(p, f)
+		}
+		if err != nil {
+			deleteShaders(shaders)
+			return nil, err
+		}
+		sh, err := CompileShader(src.Text, stage)
+		if err != nil {
+			deleteShaders(shaders)
+			if se, ok := err.(*ShaderError); ok {
+				se.File = file
+				se.Log = src.TranslateLog(se.Log)
+			}
+			return nil, err
+		}
+		shaders = append(shaders, sh)
+	}
+	return shaders, nil
+}
+
+// stat records the modification time of a file of the program
+func (lib *ShaderLibrary) stat(p *libProgram, file string) {
+	if fi, err := fs.Stat(lib.fsys, file); err == nil {
+		p.mtime[file] = fi.ModTime()
+	}
+}
+
+func deleteShaders(shaders []Shader) {
+	for _, sh := range shaders {
+		sh.Delete()
+	}
+}
@@ -0,0 +1,284 @@
+package shapes
+
+import "math"
+
+// FullscreenQuad returns a quad covering the viewport in clip space
+// (from -1 to 1 in X and Y, at Z = 0) facing +Z
+func FullscreenQuad() *Shape {
+	var s Shape
+	n := vec3{0, 0, 1}
+	a := s.add(vec3{-1, -1, 0}, n, 0, 0)
+	b := s.add(vec3{1, -1, 0}, n, 1, 0)
+	c := s.add(vec3{1, 1, 0}, n, 1, 1)
+	d := s.add(vec3{-1, 1, 0}, n, 0, 1)
+	s.quad(a, b, c, d)
+	s.computeTangents()
+	return &s
+}
+
+// Plane returns a grid in the XZ plane, centered in the origin and facing +Y
+// divided in subdivX x subdivZ quads
+func Plane(width, depth float32, subdivX, subdivZ int) *Shape {
+	var s Shape
+	w, d := float64(width), float64(depth)
+	s.grid(max1(subdivX), max1(subdivZ), func(u, v float64) (vec3, vec3) {
+		return vec3{(u - 0.5) * w, 0, (0.5 - v) * d}, vec3{0, 1, 0}
+	})
+	s.computeTangents()
+	return &s
+}
+
+// Cube returns a cube centered in the origin with the given side
+// Each face has its own vertices, with UVs covering the whole texture
+func Cube(side float32) *Shape {
+	var s Shape
+	h := float64(side) / 2
+	faces := [6][3]vec3{ // Normal, U direction and V direction
+		{{1, 0, 0}, {0, 0, -1}, {0, 1, 0}},
+		{{-1, 0, 0}, {0, 0, 1}, {0, 1, 0}},
+		{{0, 1, 0}, {1, 0, 0}, {0, 0, -1}},
+		{{0, -1, 0}, {1, 0, 0}, {0, 0, 1}},
+		{{0, 0, 1}, {1, 0, 0}, {0, 1, 0}},
+		{{0, 0, -1}, {-1, 0, 0}, {0, 1, 0}},
+	}
+	for _, f := range faces {
+		n, u, v := f[0], f[1].scale(h), f[2].scale(h)
+		c := n.scale(h)
+		a := s.add(c.sub(u).sub(v), n, 0, 0)
+		b := s.add(c.add(u).sub(v), n, 1, 0)
+		d := s.add(c.add(u).add(v), n, 1, 1)
+		e := s.add(c.sub(u).add(v), n, 0, 1)
+		s.quad(a, b, d, e)
+	}
+	s.computeTangents()
+	return &s
+}
+
+// UVSphere returns a sphere centered in the origin, made of segments around
+// the Y axis and rings from the south to the north pole
+func UVSphere(radius float32, segments, rings int) *Shape {
+	var s Shape
+	r := float64(radius)
+	if rings < 2 {
+		rings = 2
+	}
+	s.grid(max3(segments), rings, func(u, v float64) (vec3, vec3) {
+		phi, lat := u*2*math.Pi, (v-0.5)*math.Pi
+		n := vec3{math.Cos(lat) * math.Sin(phi), math.Sin(lat), math.Cos(lat) * math.Cos(phi)}
+		return n.scale(r), n
+	})
+	s.computeTangents()
+	return &s
+}
+
+// Icosphere returns a sphere centered in the origin obtained by subdividing
+// an icosahedron: subdivisions 0 is the icosahedron, each level multiplies by
+// 4 the number of triangles. The triangles are more uniform than UVSphere
+func Icosphere(radius float32, subdivisions int) *Shape {
+	t := (1 + math.Sqrt(5)) / 2
+	verts := []vec3{
+		{-1, t, 0}, {1, t, 0}, {-1, -t, 0}, {1, -t, 0},
+		{0, -1, t}, {0, 1, t}, {0, -1, -t}, {0, 1, -t},
+		{t, 0, -1}, {t, 0, 1}, {-t, 0, -1}, {-t, 0, 1},
+	}
+	for i := range verts {
+		verts[i] = verts[i].normalize()
+	}
+	faces := [][3]uint32{
+		{0, 11, 5}, {0, 5, 1}, {0, 1, 7}, {0, 7, 10}, {0, 10, 11},
+		{1, 5, 9}, {5, 11, 4}, {11, 10, 2}, {10, 7, 6}, {7, 1, 8},
+		{3, 9, 4}, {3, 4, 2}, {3, 2, 6}, {3, 6, 8}, {3, 8, 9},
+		{4, 9, 5}, {2, 4, 11}, {6, 2, 10}, {8, 6, 7}, {9, 8, 1},
+	}
+
+	for i := 0; i < subdivisions; i++ {
+		mids := make(map[[2]uint32]uint32)
+		mid := func(a, b uint32) uint32 {
+			key := [2]uint32{a, b}
+			if a > b {
+				key = [2]uint32{b, a}
+			}
+			if m, ok := mids[key]; ok {
+				return m
+			}
+			verts = append(verts, verts[a].add(verts[b]).normalize())
+			mids[key] = uint32(len(verts) - 1)
+			return mids[key]
+		}
+		next := make([][3]uint32, 0, len(faces)*4)
+		for _, f := range faces {
+			ab, bc, ca := mid(f[0], f[1]), mid(f[1], f[2]), mid(f[2], f[0])
+			next = append(next,
+				[3]uint32{f[0], ab, ca}, [3]uint32{f[1], bc, ab},
+				[3]uint32{f[2], ca, bc}, [3]uint32{ab, bc, ca})
+		}
+		faces = next
+	}
+
+	var s Shape
+	r := float64(radius)
+	for _, n := range verts {
+		u := 0.5 + math.Atan2(n[0], n[2])/(2*math.Pi)
+		v := 0.5 + math.Asin(n[1])/math.Pi
+		s.add(n.scale(r), n, u, v)
+	}
+	// Triangles crossing the seam of the UV mapping have vertices on both
+	// sides: duplicate the ones with small U, moving them past 1
+	seam := make(map[uint32]uint32)
+	for _, f := range faces {
+		us := [3]float32{s.Vertices[f[0]].UV[0], s.Vertices[f[1]].UV[0], s.Vertices[f[2]].UV[0]}
+		minU := math.Min(float64(us[0]), math.Min(float64(us[1]), float64(us[2])))
+		maxU := math.Max(float64(us[0]), math.Max(float64(us[1]), float64(us[2])))
+		if maxU-minU > 0.5 {
+			for i := range f {
+				if us[i] < 0.5 {
+					dup, ok := seam[f[i]]
+					if !ok {
+						v := s.Vertices[f[i]]
+						v.UV[0]++
+						s.Vertices = append(s.Vertices, v)
+						dup = uint32(len(s.Vertices) - 1)
+						seam[f[i]] = dup
+					}
+					f[i] = dup
+				}
+			}
+		}
+		s.tri(f[0], f[1], f[2])
+	}
+	s.computeTangents()
+	return &s
+}
+
+// Cylinder returns a cylinder centered in the origin with the axis along Y
+// and closed by two caps
+func Cylinder(radius, height float32, segments int) *Shape {
+	var s Shape
+	r, h := float64(radius), float64(height)
+	s.grid(max3(segments), 1, func(u, v float64) (vec3, vec3) {
+		phi := u * 2 * math.Pi
+		n := vec3{math.Sin(phi), 0, math.Cos(phi)}
+		return vec3{r * n[0], (v - 0.5) * h, r * n[2]}, n
+	})
+	s.disc(r, h/2, max3(segments), true)
+	s.disc(r, -h/2, max3(segments), false)
+	s.computeTangents()
+	return &s
+}
+
+// Cone returns a cone with the base centered in y = -height/2 and the apex
+// in y = height/2, closed by the base
+func Cone(radius, height float32, segments int) *Shape {
+	var s Shape
+	r, h := float64(radius), float64(height)
+	s.grid(max3(segments), 1, func(u, v float64) (vec3, vec3) {
+		phi := u * 2 * math.Pi
+		sin, cos := math.Sin(phi), math.Cos(phi)
+		return vec3{r * (1 - v) * sin, (v - 0.5) * h, r * (1 - v) * cos}, vec3{h * sin, r, h * cos}
+	})
+	s.disc(r, -h/2, max3(segments), false)
+	s.computeTangents()
+	return &s
+}
+
+// Torus returns a torus centered in the origin lying in the XZ plane
+// major is the distance from the center to the center of the tube, minor is
+// the radius of the tube. segments go around Y, sides around the tube
+func Torus(major, minor float32, segments, sides int) *Shape {
+	var s Shape
+	R, r := float64(major), float64(minor)
+	s.grid(max3(segments), max3(sides), func(u, v float64) (vec3, vec3) {
+		phi, theta := u*2*math.Pi, v*2*math.Pi
+		n := vec3{math.Cos(theta) * math.Sin(phi), math.Sin(theta), math.Cos(theta) * math.Cos(phi)}
+		c := vec3{R * math.Sin(phi), 0, R * math.Cos(phi)}
+		return c.add(n.scale(r)), n
+	})
+	s.computeTangents()
+	return &s
+}
+
+// Capsule returns a cylinder with hemispherical caps, centered in the origin
+// with the axis along Y. height is the length of the cylindrical part, the
+// total height is height + 2*radius. rings is the number of rings in each cap
+func Capsule(radius, height float32, segments, rings int) *Shape {
+	var s Shape
+	r, h := float64(radius), float64(height)
+	segments, rings = max3(segments), max1(rings)
+	// Rows of vertices: latitude and vertical offset, from south to north
+	type row struct{ lat, y float64 }
+	var rows []row
+	for i := 0; i <= rings; i++ {
+		rows = append(rows, row{(float64(i)/float64(rings) - 1) * math.Pi / 2, -h / 2})
+	}
+	for i := 0; i <= rings; i++ {
+		rows = append(rows, row{float64(i) / float64(rings) * math.Pi / 2, h / 2})
+	}
+	total := h + 2*r
+	base := uint32(len(s.Vertices))
+	for _, rw := range rows {
+		y := r*math.Sin(rw.lat) + rw.y
+		for c := 0; c <= segments; c++ {
+			u := float64(c) / float64(segments)
+			phi := u * 2 * math.Pi
+			n := vec3{math.Cos(rw.lat) * math.Sin(phi), math.Sin(rw.lat), math.Cos(rw.lat) * math.Cos(phi)}
+			s.add(vec3{r * n[0], y, r * n[2]}, n, u, (y+total/2)/total)
+		}
+	}
+	stride := uint32(segments + 1)
+	for i := uint32(0); i+1 < uint32(len(rows)); i++ {
+		for c := uint32(0); c < uint32(segments); c++ {
+			a := base + i*stride + c
+			s.quad(a, a+1, a+1+stride, a+stride)
+		}
+	}
+	s.computeTangents()
+	return &s
+}
+
+// Disc returns a disc in the XZ plane centered in the origin and facing +Y
+func Disc(radius float32, segments int) *Shape {
+	var s Shape
+	s.disc(float64(radius), 0, max3(segments), true)
+	s.computeTangents()
+	return &s
+}
+
+// disc appends a disc at height y facing +Y if up is true, -Y otherwise
+func (s *Shape) disc(r, y float64, segments int, up bool) {
+	n := vec3{0, 1, 0}
+	if !up {
+		n = vec3{0, -1, 0}
+	}
+	center := s.add(vec3{0, y, 0}, n, 0.5, 0.5)
+	for i := 0; i <= segments; i++ {
+		phi := float64(i) / float64(segments) * 2 * math.Pi
+		sin, cos := math.Sin(phi), math.Cos(phi)
+		v := 0.5 + 0.5*cos
+		if !up {
+			v = 0.5 - 0.5*cos
+		}
+		s.add(vec3{r * sin, y, r * cos}, n, 0.5+0.5*sin, v)
+	}
+	for i := uint32(1); i <= uint32(segments); i++ {
+		if up {
+			s.tri(center, center+i, center+i+1)
+		} else {
+			s.tri(center, center+i+1, center+i)
+		}
+	}
+}
+
+// max1 and max3 clamp the subdivisions to a minimum meaningful value
+func max1(n int) int {
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+func max3(n int) int {
+	if n < 3 {
+		return 3
+	}
+	return n
+}
@@ -0,0 +1,166 @@
+// Package shapes generates the geometry of common shapes, useful for
+// debugging and prototyping. Every shape is indexed and has positions,
+// normals, tangents and texture coordinates. Front faces are counter-clockwise
+// when seen from outside. Generation is pure Go: the result can be used with
+// glad.NewMesh (see Vertex) or with glad.Config using Data and Elements
+package shapes
+
+import "math"
+
+// Vertex is a vertex of a shape, its layout can be used with glad.NewMesh
+type Vertex struct {
+	Pos     [3]float32 `glad:"pos"`
+	Normal  [3]float32 `glad:"normal"`
+	Tangent [4]float32 `glad:"tangent"` // W is the handedness of the bitangent
+	UV      [2]float32 `glad:"uv"`
+}
+
+// Shape contains the vertices and the indices of the triangles of a shape
+type Shape struct {
+	Vertices []Vertex
+	Indices  []uint32
+}
+
+// Data returns the vertex data as separate buffers, usable in glad.Config:
+// positions (3 components), normals (3), tangents (4) and UVs (2), e.g.
+//
+//	Attributes: []glad.Attr{{Buff: 0, Name: "pos", Size: 3}, {Buff: 1, Name: "normal", Size: 3}, {Buff: 2, Name: "tangent", Size: 4}, {Buff: 3, Name: "uv", Size: 2}},
+//	Data:       shape.Data(),
+//	Elements:   shape.Elements(),
+func (s *Shape) Data() [][]float32 {
+	pos := make([]float32, 0, len(s.Vertices)*3)
+	nor := make([]float32, 0, len(s.Vertices)*3)
+	tan := make([]float32, 0, len(s.Vertices)*4)
+	uvs := make([]float32, 0, len(s.Vertices)*2)
+	for _, v := range s.Vertices {
+		pos = append(pos, v.Pos[:]...)
+		nor = append(nor, v.Normal[:]...)
+		tan = append(tan, v.Tangent[:]...)
+		uvs = append(uvs, v.UV[:]...)
+	}
+	return [][]float32{pos, nor, tan, uvs}
+}
+
+// Elements returns the indices as int16, usable as glad.Config Elements
+// It panics if the shape has too many vertices to be indexed with int16
+func (s *Shape) Elements() []int16 {
+	if len(s.Vertices) > math.MaxInt16 {
+		panic("Shape has too many vertices for 16 bit elements")
+	}
+	el := make([]int16, len(s.Indices))
+	for i, idx := range s.Indices {
+		el[i] = int16(idx)
+	}
+	return el
+}
+
+// vec3 is used for computations in double precision
+type vec3 [3]float64
+
+func (a vec3) add(b vec3) vec3      { return vec3{a[0] + b[0], a[1] + b[1], a[2] + b[2]} }
+func (a vec3) sub(b vec3) vec3      { return vec3{a[0] - b[0], a[1] - b[1], a[2] - b[2]} }
+func (a vec3) scale(k float64) vec3 { return vec3{a[0] * k, a[1] * k, a[2] * k} }
+func (a vec3) dot(b vec3) float64   { return a[0]*b[0] + a[1]*b[1] + a[2]*b[2] }
+func (a vec3) length() float64      { return math.Sqrt(a.dot(a)) }
+func (a vec3) f32() [3]float32      { return [3]float32{float32(a[0]), float32(a[1]), float32(a[2])} }
+func vecOf(v [3]float32) vec3       { return vec3{float64(v[0]), float64(v[1]), float64(v[2])} }
+func (a vec3) cross(b vec3) vec3 {
+	return vec3{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+}
+
+func (a vec3) normalize() vec3 {
+	l := a.length()
+	if l == 0 {
+		return a
+	}
+	return a.scale(1 / l)
+}
+
+// add appends a vertex, returning its index
+func (s *Shape) add(pos, normal vec3, u, v float64) uint32 {
+	s.Vertices = append(s.Vertices, Vertex{
+		Pos:    pos.f32(),
+		Normal: normal.normalize().f32(),
+		UV:     [2]float32{float32(u), float32(v)},
+	})
+	return uint32(len(s.Vertices) - 1)
+}
+
+// tri appends a triangle
+func (s *Shape) tri(a, b, c uint32) {
+	s.Indices = append(s.Indices, a, b, c)
+}
+
+// quad appends two triangles for the quad with vertices in CCW order
+func (s *Shape) quad(a, b, c, d uint32) {
+	s.tri(a, b, c)
+	s.tri(a, c, d)
+}
+
+// grid appends a surface parametrized by u and v in [0, 1], divided in
+// cols x rows quads. surf returns position and normal of the point (u, v):
+// for the faces to be CCW, dP/du x dP/dv must point toward the normal
+func (s *Shape) grid(cols, rows int, surf func(u, v float64) (pos, normal vec3)) {
+	base := uint32(len(s.Vertices))
+	for r := 0; r <= rows; r++ {
+		v := float64(r) / float64(rows)
+		for c := 0; c <= cols; c++ {
+			u := float64(c) / float64(cols)
+			p, n := surf(u, v)
+			s.add(p, n, u, v)
+		}
+	}
+	stride := uint32(cols + 1)
+	for r := uint32(0); r < uint32(rows); r++ {
+		for c := uint32(0); c < uint32(cols); c++ {
+			i := base + r*stride + c
+			s.quad(i, i+1, i+1+stride, i+stride)
+		}
+	}
+}
+
+// computeTangents sets the tangents of the vertices from the UV mapping
+// Vertices where the mapping is degenerate get a tangent orthogonal to the normal
+func (s *Shape) computeTangents() {
+	tan := make([]vec3, len(s.Vertices))
+	bit := make([]vec3, len(s.Vertices))
+	for i := 0; i+2 < len(s.Indices); i += 3 {
+		a, b, c := s.Indices[i], s.Indices[i+1], s.Indices[i+2]
+		va, vb, vc := s.Vertices[a], s.Vertices[b], s.Vertices[c]
+		e1, e2 := vecOf(vb.Pos).sub(vecOf(va.Pos)), vecOf(vc.Pos).sub(vecOf(va.Pos))
+		du1, dv1 := float64(vb.UV[0]-va.UV[0]), float64(vb.UV[1]-va.UV[1])
+		du2, dv2 := float64(vc.UV[0]-va.UV[0]), float64(vc.UV[1]-va.UV[1])
+		det := du1*dv2 - du2*dv1
+		if math.Abs(det) < 1e-12 {
+			continue
+		}
+		r := 1 / det
+		t := e1.scale(dv2 * r).sub(e2.scale(dv1 * r))
+		bt := e2.scale(du1 * r).sub(e1.scale(du2 * r))
+		for _, idx := range [3]uint32{a, b, c} {
+			tan[idx] = tan[idx].add(t)
+			bit[idx] = bit[idx].add(bt)
+		}
+	}
+	for i := range s.Vertices {
+		n := vecOf(s.Vertices[i].Normal)
+		// Gram-Schmidt orthogonalization
+		t := tan[i].sub(n.scale(n.dot(tan[i]))).normalize()
+		if t.length() < 0.5 {
+			t = orthogonal(n)
+		}
+		w := 1.0
+		if n.cross(t).dot(bit[i]) < 0 {
+			w = -1
+		}
+		s.Vertices[i].Tangent = [4]float32{float32(t[0]), float32(t[1]), float32(t[2]), float32(w)}
+	}
+}
+
+// orthogonal returns a unit vector orthogonal to n
+func orthogonal(n vec3) vec3 {
+	if math.Abs(n[0]) < 0.9 {
+		return n.cross(vec3{1, 0, 0}).normalize()
+	}
+	return n.cross(vec3{0, 1, 0}).normalize()
+}
@@ -0,0 +1,116 @@
+package shapes
+
+import (
+	"math"
+	"testing"
+)
+
+func TestShapes(t *testing.T) {
+	tests := []struct {
+		name     string
+		shape    *Shape
+		vertices int // -1 if not known in advance
+		indices  int
+		convex   bool // Closed and convex around the origin
+	}{
+		{"FullscreenQuad", FullscreenQuad(), 4, 6, false},
+		{"Plane", Plane(2, 3, 4, 5), 5 * 6, 4 * 5 * 6, false},
+		{"PlaneClamped", Plane(1, 1, 0, -1), 4, 6, false},
+		{"Cube", Cube(2), 24, 36, true},
+		{"UVSphere", UVSphere(1, 16, 8), 17 * 9, 16 * 8 * 6, true},
+		{"Icosphere0", Icosphere(1, 0), -1, 20 * 3, true},
+		{"Icosphere2", Icosphere(2, 2), -1, 20 * 16 * 3, true},
+		{"Cylinder", Cylinder(1, 2, 12), 13*2 + 2*14, 12*6 + 2*12*3, true},
+		{"Cone", Cone(1, 2, 12), 13*2 + 14, 12*6 + 12*3, true},
+		{"Torus", Torus(2, 0.5, 16, 8), 17 * 9, 16 * 8 * 6, false},
+		{"Capsule", Capsule(1, 2, 12, 4), 10 * 13, 9 * 12 * 6, true},
+		{"Disc", Disc(1, 10), 12, 10 * 3, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.shape
+			if tt.vertices >= 0 && len(s.Vertices) != tt.vertices {
+				t.Errorf("got %d vertices, want %d", len(s.Vertices), tt.vertices)
+			}
+			if len(s.Indices) != tt.indices {
+				t.Errorf("got %d indices, want %d", len(s.Indices), tt.indices)
+			}
+			checkIndices(t, s)
+			checkWinding(t, s, tt.convex)
+			checkVertices(t, s)
+		})
+	}
+}
+
+func checkIndices(t *testing.T, s *Shape) {
+	t.Helper()
+	if len(s.Indices)%3 != 0 {
+		t.Fatalf("%d indices is not a multiple of 3", len(s.Indices))
+	}
+	for i, idx := range s.Indices {
+		if int(idx) >= len(s.Vertices) {
+			t.Fatalf("index %d is %d, out of %d vertices", i, idx, len(s.Vertices))
+		}
+	}
+}
+
+// checkWinding verifies that the triangles are CCW seen from the side of the
+// vertex normals and, for convex shapes, from outside
+func checkWinding(t *testing.T, s *Shape, convex bool) {
+	t.Helper()
+	for i := 0; i < len(s.Indices); i += 3 {
+		a, b, c := s.Vertices[s.Indices[i]], s.Vertices[s.Indices[i+1]], s.Vertices[s.Indices[i+2]]
+		pa, pb, pc := vecOf(a.Pos), vecOf(b.Pos), vecOf(c.Pos)
+		face := pb.sub(pa).cross(pc.sub(pa))
+		if face.length() < 1e-9 {
+			continue // Degenerate triangles at the poles and apexes
+		}
+		n := vecOf(a.Normal).add(vecOf(b.Normal)).add(vecOf(c.Normal))
+		if face.dot(n) <= 0 {
+			t.Fatalf("triangle %d is not CCW around its normals: %v %v %v", i/3, a.Pos, b.Pos, c.Pos)
+		}
+		center := pa.add(pb).add(pc).scale(1.0 / 3)
+		if convex && face.dot(center) <= 0 {
+			t.Fatalf("triangle %d faces inward: %v %v %v", i/3, a.Pos, b.Pos, c.Pos)
+		}
+	}
+}
+
+func checkVertices(t *testing.T, s *Shape) {
+	t.Helper()
+	const eps = 1e-5
+	for i, v := range s.Vertices {
+		n := vecOf(v.Normal)
+		if math.Abs(n.length()-1) > eps {
+			t.Fatalf("vertex %d: normal %v is not unit", i, v.Normal)
+		}
+		for _, x := range v.Tangent {
+			if math.IsNaN(float64(x)) || math.IsInf(float64(x), 0) {
+				t.Fatalf("vertex %d: tangent %v is not finite", i, v.Tangent)
+			}
+		}
+		tan := vec3{float64(v.Tangent[0]), float64(v.Tangent[1]), float64(v.Tangent[2])}
+		if math.Abs(tan.length()-1) > eps || math.Abs(tan.dot(n)) > eps {
+			t.Fatalf("vertex %d: tangent %v is not unit and orthogonal to normal %v", i, v.Tangent, v.Normal)
+		}
+		if v.Tangent[3] != 1 && v.Tangent[3] != -1 {
+			t.Fatalf("vertex %d: tangent handedness is %v", i, v.Tangent[3])
+		}
+	}
+}
+
+func TestElements(t *testing.T) {
+	s := Cube(1)
+	el := s.Elements()
+	for i, idx := range s.Indices {
+		if uint32(el[i]) != idx {
+			t.Fatalf("element %d is %d, want %d", i, el[i], idx)
+		}
+	}
+	data := s.Data()
+	for i, comps := range []int{3, 3, 4, 2} {
+		if len(data[i]) != len(s.Vertices)*comps {
+			t.Errorf("buffer %d has %d values, want %d", i, len(data[i]), len(s.Vertices)*comps)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package glad
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+// StateCache tracks the objects bound to an OpenGL context, so that binding
+// an object which is already bound does not result in a GL call.
+// The cache is optional: use UseStateCache to activate it for the current
+// context. Wrappers like Program.Use, VertexArrayObject.Bind, Texture.Bind and
+// FramebufferObject.Bind will then skip the calls that would not change state.
+// Each context has its own cache, found from the context current on the
+// calling thread, so contexts on other threads (e.g. a Loader) do not affect
+// it. The cache knows only about calls made through this package: if you call
+// gl functions that change the bindings directly, call Invalidate afterwards
+type StateCache struct {
+	mu     sync.Mutex
+	bound  map[bindPoint]uint32 // Name bound to each known binding point
+	issued int                  // Number of binding calls sent to GL
+	saved  int                  // Number of binding calls skipped
+}
+
+// Kind of binding points tracked by the cache
+const (
+	bindProgram = iota
+	bindVertexArray
+	bindTexture
+	bindSampler
+	bindFramebuffer
+	bindRenderbuffer
+	bindBuffer
+)
+
+// bindPoint identifies a binding point in the context: index is the texture
+// unit for textures and samplers, the target for buffers and framebuffers
+type bindPoint struct {
+	kind  int
+	index uint32
+}
+
+// stateCaches holds the cache of each context, identified by its window
+var stateCaches sync.Map
+
+// stateCacheCount is the number of caches in use, to skip looking up the
+// current context when there are none
+var stateCacheCount int32
+
+// NewStateCache creates a cache where the state of every binding is unknown
+func NewStateCache() *StateCache {
+	return &StateCache{bound: make(map[bindPoint]uint32)}
+}
+
+// UseStateCache sets the cache used by the wrappers for the current context
+// A cache must not be shared by different contexts, since each one has its
+// own bindings. Pass nil to disable the cache for the current context
+func UseStateCache(sc *StateCache) {
+	setStateCache(glfw.GetCurrentContext(), sc)
+}
+
+// setStateCache sets or removes (if sc is nil) the cache of a context
+func setStateCache(ctx *glfw.Window, sc *StateCache) {
+	if ctx == nil {
+		return
+	}
+	if sc == nil {
+		if _, ok := stateCaches.LoadAndDelete(ctx); ok {
+			atomic.AddInt32(&stateCacheCount, -1)
+		}
+		return
+	}
+	if _, loaded := stateCaches.LoadOrStore(ctx, sc); loaded {
+		stateCaches.Store(ctx, sc)
+		return
+	}
+	atomic.AddInt32(&stateCacheCount, 1)
+}
+
+// CurrentStateCache returns the cache of the current context, nil if no cache
+// is used
+func CurrentStateCache() *StateCache {
+	if atomic.LoadInt32(&stateCacheCount) == 0 {
+		return nil
+	}
+	ctx := glfw.GetCurrentContext()
+	if ctx == nil {
+		return nil
+	}
+	if sc, ok := stateCaches.Load(ctx); ok {
+		return sc.(*StateCache)
+	}
+	return nil
+}
+
+// stateCache is the cache of the current context used by the wrappers, nil
+// if disabled. Its methods can be called on nil
+func stateCache() *StateCache {
+	return CurrentStateCache()
+}
+
+// Invalidate forgets all the bindings, so that the next bind of any object
+// will be sent to GL. Call this after changing bindings with raw gl functions
+func (sc *StateCache) Invalidate() {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.bound = make(map[bindPoint]uint32)
+}
+
+// Counters returns the number of binding calls that were sent to GL and
+// the number of calls that were skipped because they would be no-ops
+func (sc *StateCache) Counters() (issued, saved int) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.issued, sc.saved
+}
+
+// ResetCounters sets the issued and saved counters to zero
+func (sc *StateCache) ResetCounters() {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.issued, sc.saved = 0, 0
+}
+
+// bind records that name is going to be bound to the binding point and
+// returns true if the GL call has to be issued. It is safe to call on a nil
+// cache, in which case the call is always issued
+func (sc *StateCache) bind(kind int, index, name uint32) bool {
+	if sc == nil {
+		return true
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	p := bindPoint{kind, index}
+	if cur, ok := sc.bound[p]; ok && cur == name {
+		sc.saved++
+		return false
+	}
+	sc.bound[p] = name
+	sc.issued++
+	return true
+}
+
+// bindFramebuffer is like bind, but for the gl.FRAMEBUFFER target, which
+// sets both the draw and read framebuffer bindings
+func (sc *StateCache) bindFramebuffer(name uint32) bool {
+	if sc == nil {
+		return true
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	draw := bindPoint{bindFramebuffer, gl.DRAW_FRAMEBUFFER}
+	read := bindPoint{bindFramebuffer, gl.READ_FRAMEBUFFER}
+	d, okd := sc.bound[draw]
+	r, okr := sc.bound[read]
+	if okd && okr && d == name && r == name {
+		sc.saved++
+		return false
+	}
+	sc.bound[draw], sc.bound[read] = name, name
+	sc.issued++
+	return true
+}
+
+// bindVertexArray is like bind, but since the element buffer binding is part
+// of the VAO state, it is forgotten when a different VAO is bound
+func (sc *StateCache) bindVertexArray(name uint32) bool {
+	if !sc.bind(bindVertexArray, 0, name) {
+		return false
+	}
+	if sc != nil {
+		sc.mu.Lock()
+		delete(sc.bound, bindPoint{bindBuffer, gl.ELEMENT_ARRAY_BUFFER})
+		sc.mu.Unlock()
+	}
+	return true
+}
+
+// forget records that a deleted object is no longer bound anywhere
+// as GL reverts to 0 the bindings of deleted objects
+func (sc *StateCache) forget(kind int, name uint32) {
+	if sc == nil {
+		return
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	for p, n := range sc.bound {
+		if p.kind == kind && n == name {
+			sc.bound[p] = 0
+		}
+	}
+}
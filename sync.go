@@ -0,0 +1,128 @@
+package glad
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+)
+
+// Fence represents a sync object in the OpenGL context
+// A fence is inserted in the command stream and becomes signaled when the
+// GPU completes all the commands issued before it. This allows to know when
+// data written by the GPU (e.g. before reading it with Texture.GetImage) or
+// read by the GPU (e.g. a region of a buffer updated with BufferSubData32)
+// can be safely accessed without stalling
+type Fence uintptr
+
+// NewFence inserts a new fence in the command stream
+func NewFence() Fence {
+	checkThread()
+	return Fence(gl.FenceSync(gl.SYNC_GPU_COMMANDS_COMPLETE, 0))
+}
+
+// Delete the fence
+func (f Fence) Delete() {
+	checkThread()
+	gl.DeleteSync(uintptr(f))
+}
+
+// Signaled returns true if the commands before the fence are completed
+// This does not block
+func (f Fence) Signaled() bool {
+	checkThread()
+	var status int32
+	gl.GetSynciv(uintptr(f), gl.SYNC_STATUS, 1, nil, &status)
+	return status == gl.SIGNALED
+}
+
+// ClientWait blocks the caller until the fence is signaled or the timeout
+// expires, returning true in the first case. Pending commands are flushed,
+// so that the fence is guaranteed to be signaled eventually
+func (f Fence) ClientWait(timeout time.Duration) (bool, error) {
+	checkThread()
+	switch gl.ClientWaitSync(uintptr(f), gl.SYNC_FLUSH_COMMANDS_BIT, uint64(timeout)) {
+	case gl.ALREADY_SIGNALED, gl.CONDITION_SATISFIED:
+		return true, nil
+	case gl.TIMEOUT_EXPIRED:
+		return false, nil
+	default:
+		return false, errors.New("glClientWaitSync failed")
+	}
+}
+
+// Wait makes the GL server wait for the fence before executing the next
+// commands. The caller is not blocked: this is useful to synchronize
+// commands issued on different contexts
+func (f Fence) Wait() {
+	checkThread()
+	gl.WaitSync(uintptr(f), 0, gl.TIMEOUT_IGNORED)
+}
+
+// FrameSync limits the number of frames the CPU can prepare in advance of
+// the GPU. Each frame in flight has its own slot: resources indexed by slot
+// (e.g. regions of a buffer) are not used by the GPU once Begin returns, so
+// they can be safely rewritten. Typical usage is
+//
+//	fs := glad.NewFrameSync(3)
+//	for !win.ShouldClose() {
+//		slot := fs.Begin()
+//		vbo.BufferSubData32(data, slot*regionSize)
+//		// ... draw using region slot ...
+//		fs.End()
+//	}
+type FrameSync struct {
+	fences []Fence
+	slot   int
+}
+
+// NewFrameSync creates a FrameSync allowing up to inFlight frames in flight
+func NewFrameSync(inFlight int) *FrameSync {
+	if inFlight < 1 {
+		inFlight = 1
+	}
+	return &FrameSync{fences: make([]Fence, inFlight)}
+}
+
+// Begin waits until the GPU has completed the frame that last used the
+// current slot, and returns the slot
+func (fs *FrameSync) Begin() int {
+	if f := fs.fences[fs.slot]; f != 0 {
+		for {
+			done, err := f.ClientWait(time.Second)
+			if err != nil {
+				log.Println("FrameSync:", err)
+				break
+			}
+			if done {
+				break
+			}
+		}
+		f.Delete()
+		fs.fences[fs.slot] = 0
+	}
+	return fs.slot
+}
+
+// End marks the end of the commands of the current frame and moves to the
+// next slot
+func (fs *FrameSync) End() {
+	fs.fences[fs.slot] = NewFence()
+	fs.slot = (fs.slot + 1) % len(fs.fences)
+}
+
+// Slots returns the maximum number of frames in flight
+func (fs *FrameSync) Slots() int {
+	return len(fs.fences)
+}
+
+// Delete frees the pending fences
+func (fs *FrameSync) Delete() {
+	for i, f := range fs.fences {
+		if f != 0 {
+			f.Delete()
+			fs.fences[i] = 0
+		}
+	}
+}
@@ -3,7 +3,10 @@ package glad
 import (
 	"image"
 	"image/draw"
+	_ "image/jpeg" // Formats supported by LoadTexture
+	_ "image/png"
 	"log"
+	"os"
 	"unsafe"
 
 	"github.com/go-gl/gl/v4.5-core/gl"
@@ -18,15 +21,58 @@ type Texture uint32
 // texture dimensionality, which is tied to the dimensionality of the sampler.
 // After creating the texture, you might want to attach storage to it
 func NewTexture(target uint32) Texture {
+	checkThread()
 	var tex uint32
 	gl.CreateTextures(target, 1, &tex)
+	registry.track("Texture", tex)
 	return Texture(tex)
 }
 
+// NewTextureFromImage creates a 2D texture with a copy of the image
+// Storage is RGBA8 with a full mipmap chain, which is generated
+func NewTextureFromImage(img image.Image) Texture {
+	checkThread()
+	size := img.Bounds().Size()
+	levels := int32(1)
+	for s := size.X | size.Y; s > 1; s >>= 1 {
+		levels++
+	}
+	tex := NewTexture(gl.TEXTURE_2D)
+	tex.Storage(levels, gl.RGBA8, []int{size.X, size.Y})
+	tex.Image2D(img)
+	tex.GenerateMipmap()
+	tex.SetFilters(gl.LINEAR, gl.LINEAR_MIPMAP_LINEAR)
+	return tex
+}
+
+// LoadTexture reads an image file (PNG or JPEG) into a new 2D texture
+// See NewTextureFromImage
+func LoadTexture(path string) (Texture, error) {
+	img, err := decodeImage(path)
+	if err != nil {
+		return 0, err
+	}
+	return NewTextureFromImage(img), nil
+}
+
+// decodeImage reads an image file in one of the registered formats
+func decodeImage(path string) (image.Image, error) {
+	r, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	img, _, err := image.Decode(r)
+	return img, err
+}
+
 // Delete the texture freeing its name, freeing the associated storage
 func (tex Texture) Delete() {
+	checkThread()
 	t := uint32(tex)
 	gl.DeleteTextures(1, &t)
+	stateCache().forget(bindTexture, t)
+	registry.untrack("Texture", t)
 }
 
 // Bind the texture to the specified texture unit
@@ -35,17 +81,24 @@ func (tex Texture) Delete() {
 // different textures: bind a texture to a unit and a sampler to the same unit
 // to access the texture data from the shader
 func (tex Texture) Bind(unit uint32) {
-	gl.BindTextureUnit(unit, uint32(tex))
+	checkThread()
+	if stateCache().bind(bindTexture, unit, uint32(tex)) {
+		gl.BindTextureUnit(unit, uint32(tex))
+	}
 }
 
 // Unbind the texture from the texture unit
 func (tex Texture) Unbind(unit uint32) {
-	gl.BindTextureUnit(unit, 0)
+	checkThread()
+	if stateCache().bind(bindTexture, unit, 0) {
+		gl.BindTextureUnit(unit, 0)
+	}
 }
 
 // Storage allocates storage for an empty texture of given size (cast to int32)
 // format can be, for instance, gl.RGBA8
 func (tex Texture) Storage(levels int32, internalFmt uint32, size []int) {
+	checkThread()
 	switch len(size) {
 	case 1:
 		gl.TextureStorage1D(uint32(tex), levels, internalFmt, int32(size[0]))
@@ -56,6 +109,7 @@ func (tex Texture) Storage(levels int32, internalFmt uint32, size []int) {
 	default:
 		log.Fatalln("Texture Storage must have size of length 1, 2 or 3")
 	}
+	registry.resize("Texture", uint32(tex), imageSize(levels, internalFmt, size))
 }
 
 // SubImage replaces a region of the texture with the data
@@ -64,6 +118,7 @@ func (tex Texture) Storage(levels int32, internalFmt uint32, size []int) {
 // depending whether a buffer object is or is not bound to the PIXEL_UNPACK_BUFFER
 // target
 func (tex Texture) SubImage(level int32, offset, size []int, externalFmt, typ uint32, pixels unsafe.Pointer) {
+	checkThread()
 	if len(offset) != len(size) {
 		log.Fatalln("Texture SubImage offset and size must have the same length")
 	}
@@ -83,6 +138,7 @@ func (tex Texture) SubImage(level int32, offset, size []int, externalFmt, typ ui
 // Call Storage with 2D size before this
 // The passed image will be copied and can be discarded after the call
 func (tex Texture) Image2D(img image.Image) {
+	checkThread()
 	// Copy image to RGBA format, if necessary
 	var rgba *image.RGBA
 	switch img.(type) {
@@ -103,17 +159,26 @@ func (tex Texture) Image2D(img image.Image) {
 	gl.TextureSubImage2D(uint32(tex), 0, 0, 0, int32(w), int32(h), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(rgba.Pix))
 }
 
+// GenerateMipmap computes all the levels of the texture from level 0
+func (tex Texture) GenerateMipmap() {
+	checkThread()
+	gl.GenerateTextureMipmap(uint32(tex))
+}
+
 // GetImage copies texture data to host
 func (tex Texture) GetImage(level int32, fmt, typ uint32, size int32, pixels unsafe.Pointer) {
+	checkThread()
 	gl.GetTextureImage(uint32(tex), level, fmt, typ, size, pixels)
 }
 
 func (tex Texture) SetFilters(magFilter, minFilter int32) {
+	checkThread()
 	gl.TextureParameteri(uint32(tex), gl.TEXTURE_MAG_FILTER, magFilter)
 	gl.TextureParameteri(uint32(tex), gl.TEXTURE_MIN_FILTER, minFilter)
 }
 
 func (tex Texture) Clear(r, g, b, a byte) {
+	checkThread()
 	rgba := []byte{r, g, b, a}
 	gl.ClearTexImage(uint32(tex), 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(rgba))
 }
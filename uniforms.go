@@ -1 +1,60 @@
 package glad
+
+import (
+	"github.com/akiross/go-glad/mathx"
+	"github.com/go-gl/gl/v4.5-core/gl"
+)
+
+// Uniform setters write directly in the program, which does not need to be in
+// use. Vectors and matrices are arrays, so the types of glad/mathx (e.g.
+// mathx.Mat4) can be passed without conversion. Matrices are column-major
+
+// GetUniformLocation returns the location of a uniform after linking, or -1
+// if the program does not use it (setting a uniform at -1 is ignored by GL)
+func (pr Program) GetUniformLocation(name string) int32 {
+	checkThread()
+	return gl.GetUniformLocation(uint32(pr), gl.Str(name+"\x00"))
+}
+
+func (pr Program) SetUniform1i(loc int32, v int32) {
+	checkThread()
+	gl.ProgramUniform1i(uint32(pr), loc, v)
+}
+
+func (pr Program) SetUniform1f(loc int32, v float32) {
+	checkThread()
+	gl.ProgramUniform1f(uint32(pr), loc, v)
+}
+
+func (pr Program) SetUniform2f(loc int32, v [2]float32) {
+	checkThread()
+	gl.ProgramUniform2f(uint32(pr), loc, v[0], v[1])
+}
+
+func (pr Program) SetUniform3f(loc int32, v [3]float32) {
+	checkThread()
+	gl.ProgramUniform3f(uint32(pr), loc, v[0], v[1], v[2])
+}
+
+func (pr Program) SetUniform4f(loc int32, v [4]float32) {
+	checkThread()
+	gl.ProgramUniform4f(uint32(pr), loc, v[0], v[1], v[2], v[3])
+}
+
+func (pr Program) SetUniformMat3(loc int32, m [9]float32) {
+	checkThread()
+	gl.ProgramUniformMatrix3fv(uint32(pr), loc, 1, false, &m[0])
+}
+
+func (pr Program) SetUniformMat4(loc int32, m [16]float32) {
+	checkThread()
+	gl.ProgramUniformMatrix4fv(uint32(pr), loc, 1, false, &m[0])
+}
+
+// SetUniformMat4v sets an array of matrices, e.g. the bones of a skeleton
+func (pr Program) SetUniformMat4v(loc int32, ms []mathx.Mat4) {
+	checkThread()
+	if len(ms) > 0 {
+		gl.ProgramUniformMatrix4fv(uint32(pr), loc, int32(len(ms)), false, &ms[0][0])
+	}
+}
@@ -1,9 +1,10 @@
 package glad
 
 import (
+	"fmt"
 	"image"
 	"log"
-	"unsafe"
+	"strings"
 
 	"github.com/go-gl/gl/v4.5-core/gl"
 )
@@ -57,6 +58,7 @@ func MakeProgram(shaders ...Shader) Program {
 // CheckError checks for OpenGL errors and print them if any
 // Returns true if any error was found
 func CheckError() bool {
+	checkThread()
 	if err := gl.GetError(); err != gl.NO_ERROR {
 		log.Println("GL ERROR:", err)
 		return true
@@ -74,10 +76,16 @@ func CheckError() bool {
 */
 
 // Attr describes an attribute
+// Attributes with a non-zero Divisor are per-instance attributes. The divisor
+// applies to the whole buffer, so attributes reading from the same buffer must
+// have the same Divisor. Attributes are not GL objects, so their Label is
+// applied to the buffer they read from
 type Attr struct {
-	Buff int    // Which of the Data buffers will be used
-	Name string // Name of the attribute in the shader
-	Size int32  // Number of elements for this attribute
+	Buff    int    // Which of the Data buffers will be used
+	Name    string // Name of the attribute in the shader
+	Size    int32  // Number of elements for this attribute
+	Divisor uint32 // 0 for per-vertex data, n to advance every n instances
+	Label   string // If not empty, added to the label of the buffer
 }
 
 // TxrSpec describes a texture to be loaded
@@ -94,16 +102,33 @@ type Rect struct {
 
 // Config contains the specifications for automated build
 type Config struct {
-	Shaders    []Shader
-	Attributes []Attr
-	Data       [][]float32   // Multiple buffers of data (one for each VBO)
-	Elements   []int16       // Indices of elements to use. If not nil, gl.DrawElements will be used instead of gl.DrawArrays
-	DataUsages []uint32      // gl.STATIC_DRAW, etc. one for each slice in Data. If Elements is not nil, the last DataUsage is used for the EBO
-	Primitives uint32        // gl.TRIANGLES, gl.POINTS, etc.
-	ClearColor []float32     // Clear color before drawing
-	Textures   []Texture     // List of pre-existing textures to use (attached before images)
-	Images     []image.Image // Images to use to create new textures (attached after textures)
-	Offscreen  *Rect         // If not nil, will create and render to FBO setting Viewport
+	Shaders      []Shader
+	Attributes   []Attr
+	Data         [][]float32   // Multiple buffers of data (one for each VBO)
+	Elements     []int16       // Indices of elements to use. If not nil, gl.DrawElements will be used instead of gl.DrawArrays
+	DataUsages   []uint32      // gl.STATIC_DRAW, etc. one for each slice in Data. If Elements is not nil, the last DataUsage is used for the EBO
+	Instances    int32         // If greater than 0, draw this many instances using instanced drawing
+	BaseVertex   int32         // Added to the element indices when using instanced drawing
+	BaseInstance uint32        // First instance used to fetch instanced attributes
+	Primitives   uint32        // gl.TRIANGLES, gl.POINTS, etc.
+	ClearColor   []float32     // Clear color before drawing
+	Textures     []Texture     // List of pre-existing textures to use (attached before images)
+	Images       []image.Image // Images to use to create new textures (attached after textures)
+	Offscreen    *Rect         // If not nil, will create and render to FBO setting Viewport
+	Label        string        // If not empty, used to label the objects and the debug group of AutoDraw
+}
+
+// divisors returns the divisor of each Data buffer, taken from its attributes
+func (cfg *Config) divisors() []uint32 {
+	divs := make([]uint32, len(cfg.Data))
+	set := make([]bool, len(cfg.Data))
+	for _, a := range cfg.Attributes {
+		if set[a.Buff] && divs[a.Buff] != a.Divisor {
+			panic("Attributes reading from the same buffer have different divisors")
+		}
+		divs[a.Buff], set[a.Buff] = a.Divisor, true
+	}
+	return divs
 }
 
 type AutoConfig struct {
@@ -114,11 +139,13 @@ type AutoConfig struct {
 	FBO      FramebufferObject
 	VAO      VertexArrayObject
 	VBOs     []VertexBufferObject
+	EBO      VertexBufferObject // Buffer of the elements, 0 if not used
 	NumVert  int32
 	//bp      uint32 // Binding point
 }
 
 func AutoBuild(cfg *Config) *AutoConfig {
+	checkThread()
 	var mo AutoConfig
 	mo.Cfg = cfg
 
@@ -150,6 +177,7 @@ func AutoBuild(cfg *Config) *AutoConfig {
 	}
 
 	mo.VAO = NewVertexArrayObject()
+	divisors := cfg.divisors()
 	var offsets = make([]uint32, len(cfg.Data))
 	// Prepare attributes
 	for i := range cfg.Attributes {
@@ -169,6 +197,9 @@ func AutoBuild(cfg *Config) *AutoConfig {
 	// Set VBO specifiying the total stride (= sum of relative offsets)
 	for i := range cfg.Data {
 		mo.VAO.VertexBuffer32(uint32(i), mo.VBOs[i], 0, int32(offsets[i]))
+		if divisors[i] != 0 {
+			mo.VAO.BindingDivisor(uint32(i), divisors[i])
+		}
 	}
 
 	// If elements are specified, create a VBO for that
@@ -176,21 +207,24 @@ func AutoBuild(cfg *Config) *AutoConfig {
 		// The number of vertices to draw is given by elements array
 		mo.NumVert = int32(len(cfg.Elements))
 		// Now create a new Element Buffer Object
-		var ebo uint32
-		mo.VAO.Bind()
-		gl.GenBuffers(1, &ebo)
-		gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, ebo)
-		usage := cfg.DataUsages[len(cfg.DataUsages)-1]
-		gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(cfg.Elements)*2, unsafe.Pointer(&cfg.Elements[0]), usage)
-		mo.VAO.Unbind()
+		mo.EBO = NewVertexBufferObject()
+		mo.EBO.BufferData16(cfg.Elements, cfg.DataUsages[len(cfg.DataUsages)-1])
+		mo.VAO.ElementBuffer(mo.EBO)
 	} else {
-		// Compute number of vertices to draw
-		mo.NumVert = int32(len(cfg.Data[0]) / int(offsets[0]))
-		for i := 1; i < len(cfg.Data); i++ {
+		// Compute number of vertices to draw, ignoring per-instance buffers
+		mo.NumVert = -1
+		for i := range cfg.Data {
+			if divisors[i] != 0 {
+				continue
+			}
 			nv := int32(len(cfg.Data[i]) / int(offsets[i]))
-			if nv != mo.NumVert {
+			if mo.NumVert >= 0 && nv != mo.NumVert {
 				panic("Inferred number of vertices not matching")
 			}
+			mo.NumVert = nv
+		}
+		if mo.NumVert < 0 {
+			panic("At least one buffer must contain per-vertex data")
 		}
 	}
 
@@ -206,10 +240,59 @@ func AutoBuild(cfg *Config) *AutoConfig {
 		mo.Textures[i] = txr
 	}
 
+	if cfg.Label != "" {
+		mo.setLabels(cfg.Label)
+	}
+	mo.setBufferLabels()
+
 	return &mo
 }
 
+// setBufferLabels labels each VBO with the Config label and the labels of
+// the attributes reading from it, e.g. "scene VBO 0 (position, color)"
+func (mo *AutoConfig) setBufferLabels() {
+	for i := range mo.VBOs {
+		var attrs []string
+		for _, a := range mo.Cfg.Attributes {
+			if a.Buff == i && a.Label != "" {
+				attrs = append(attrs, a.Label)
+			}
+		}
+		label := strings.Join(attrs, ", ")
+		if mo.Cfg.Label != "" && label != "" {
+			label = fmt.Sprintf("%s VBO %d (%s)", mo.Cfg.Label, i, label)
+		} else if mo.Cfg.Label != "" {
+			label = fmt.Sprintf("%s VBO %d", mo.Cfg.Label, i)
+		}
+		if label != "" {
+			mo.VBOs[i].SetLabel(label)
+		}
+	}
+}
+
+// setLabels labels every object created by AutoBuild using the prefix
+func (mo *AutoConfig) setLabels(prefix string) {
+	mo.Prog.SetLabel(prefix + " program")
+	mo.VAO.SetLabel(prefix + " VAO")
+	if mo.EBO != 0 {
+		mo.EBO.SetLabel(prefix + " EBO")
+	}
+	for i := range mo.Textures {
+		mo.Textures[i].SetLabel(fmt.Sprintf("%s image %d", prefix, i))
+	}
+	if mo.Cfg.Offscreen != nil {
+		mo.FBO.SetLabel(prefix + " FBO")
+		mo.BgTxr.SetLabel(prefix + " FBO texture")
+	}
+}
+
 func (mo *AutoConfig) AutoDraw() {
+	checkThread()
+	if mo.Cfg.Label != "" {
+		PushDebugGroup(mo.Cfg.Label)
+		defer PopDebugGroup()
+	}
+
 	var bindUnit uint32
 	if mo.Cfg.Offscreen != nil {
 		mo.FBO.Bind()
@@ -238,29 +321,61 @@ func (mo *AutoConfig) AutoDraw() {
 
 	mo.Prog.Use()
 	mo.VAO.Bind()
-	if mo.Cfg.Elements == nil {
+	switch {
+	case mo.Cfg.Instances > 0 && mo.Cfg.Elements == nil:
+		gl.DrawArraysInstancedBaseInstance(mo.Cfg.Primitives, 0, mo.NumVert, mo.Cfg.Instances, mo.Cfg.BaseInstance)
+	case mo.Cfg.Instances > 0:
+		gl.DrawElementsInstancedBaseVertexBaseInstance(mo.Cfg.Primitives, mo.NumVert, gl.UNSIGNED_SHORT, nil, mo.Cfg.Instances, mo.Cfg.BaseVertex, mo.Cfg.BaseInstance)
+	case mo.Cfg.Elements == nil:
 		gl.DrawArrays(mo.Cfg.Primitives, 0, mo.NumVert)
-	} else {
+	default:
 		gl.DrawElements(mo.Cfg.Primitives, mo.NumVert, gl.UNSIGNED_SHORT, nil)
 	}
-	mo.VAO.Unbind()
 
-	for i := range mo.Textures {
-		bindUnit--
-		mo.Textures[i].Unbind(bindUnit)
-	}
+	// When the bindings are cached, leaving the objects bound allows the next
+	// call to skip binding them again
+	if stateCache() == nil {
+		mo.VAO.Unbind()
 
-	for i := range mo.Cfg.Textures {
+		for i := range mo.Textures {
+			bindUnit--
+			mo.Textures[i].Unbind(bindUnit)
+		}
+
+		for i := range mo.Cfg.Textures {
+			bindUnit--
+			mo.Cfg.Textures[i].Unbind(bindUnit)
+		}
 		bindUnit--
-		mo.Cfg.Textures[i].Unbind(bindUnit)
+		if mo.Cfg.Offscreen != nil {
+			mo.BgTxr.Unbind(bindUnit)
+		}
 	}
-	bindUnit--
 	if mo.Cfg.Offscreen != nil {
-		mo.BgTxr.Unbind(bindUnit)
 		mo.FBO.Unbind()
 	}
 }
 
+// Delete frees all the objects created by AutoBuild
+// Textures passed in Config.Textures are not deleted, as they are not owned
+func (mo *AutoConfig) Delete() {
+	mo.Prog.Delete()
+	mo.VAO.Delete()
+	for i := range mo.VBOs {
+		mo.VBOs[i].Delete()
+	}
+	if mo.EBO != 0 {
+		mo.EBO.Delete()
+	}
+	for i := range mo.Textures {
+		mo.Textures[i].Delete()
+	}
+	if mo.Cfg.Offscreen != nil {
+		mo.FBO.Delete()
+		mo.BgTxr.Delete()
+	}
+}
+
 // UpdateImage reloads the data of the i-th image into i-th texture
 func (mo *AutoConfig) UpdateImage(i int) {
 	mo.Textures[i].Image2D(mo.Cfg.Images[i])
@@ -28,31 +28,43 @@ type VertexAttrib uint32
 
 // NewVertexArrayObject allocates the name for a VAO
 func NewVertexArrayObject() VertexArrayObject {
+	checkThread()
 	var vao uint32
 	gl.CreateVertexArrays(1, &vao)
+	registry.track("VertexArrayObject", vao)
 	return VertexArrayObject(vao)
 }
 
 // Bind the VAO maing it active
 // Use this to select the vertex data to be used in the draw calls
 func (vao VertexArrayObject) Bind() {
-	gl.BindVertexArray(uint32(vao))
+	checkThread()
+	if stateCache().bindVertexArray(uint32(vao)) {
+		gl.BindVertexArray(uint32(vao))
+	}
 }
 
 // Unbind any VAO currently bound
 func (vao VertexArrayObject) Unbind() {
-	gl.BindVertexArray(0)
+	checkThread()
+	if stateCache().bindVertexArray(0) {
+		gl.BindVertexArray(0)
+	}
 }
 
 // Delete the VAO freeing the name
 func (vao VertexArrayObject) Delete() {
+	checkThread()
 	var v = uint32(vao)
 	gl.DeleteVertexArrays(1, &v)
+	stateCache().forget(bindVertexArray, v)
+	registry.untrack("VertexArrayObject", v)
 }
 
 // EnableAttrib the vertex attribute in the VAO, storing the state in the VAO
 // This tells OpenGL to read the attribute data from the buffer
 func (vao VertexArrayObject) EnableAttrib(attr VertexAttrib) {
+	checkThread()
 	gl.EnableVertexArrayAttrib(uint32(vao), uint32(attr))
 }
 
@@ -61,26 +73,46 @@ func (vao VertexArrayObject) EnableAttrib(attr VertexAttrib) {
 // it is given the same index of the attribute
 // offset and stride are in bytes
 func (vao VertexArrayObject) VertexBuffer(bindIndex uint32, buffer VertexBufferObject, offset, stride int32) {
+	checkThread()
 	gl.VertexArrayVertexBuffer(uint32(vao), bindIndex, uint32(buffer), int(offset), stride)
 }
 
 // VertexBuffer32 is like VertexBuffer but assuming we are working with 32 bit data
 func (vao VertexArrayObject) VertexBuffer32(bindIndex uint32, buffer VertexBufferObject, offset, stride int32) {
+	checkThread()
 	gl.VertexArrayVertexBuffer(uint32(vao), bindIndex, uint32(buffer), int(offset)*4, stride*4)
 }
 
 // AttribFormat specifies the format of the data associated to the attribute
 // The state of the attribute is stored in the VAO
 // size: number of components per vertex, 1, 2, 3 or 4 (e.g. 3D vertices -> 3)
-// dataType: gl.FLOAT, etc
+// dataType: gl.FLOAT, gl.HALF_FLOAT, gl.UNSIGNED_BYTE, etc, or packed formats
+// gl.INT_2_10_10_10_REV (size 4) and gl.UNSIGNED_INT_10F_11F_11F_REV (size 3)
 // normalized: define if data have to be normalized
 // stride: bytes between two vertices, 0 means they are tightly packed
 // offset: bytes of offset to the first element in the array
 func (vao VertexArrayObject) AttribFormat(attr VertexAttrib, size int32, dataType uint32, normalize bool, relativeOffset uint32) {
+	checkThread()
 	gl.VertexArrayAttribFormat(uint32(vao), uint32(attr), size, dataType, normalize, relativeOffset)
 }
 
+// AttribIFormat is like AttribFormat, but for integer attributes (e.g. ivec4)
+// The data are passed to the shader as integers, without conversion to float
+// dataType: gl.INT, gl.UNSIGNED_BYTE, etc
+func (vao VertexArrayObject) AttribIFormat(attr VertexAttrib, size int32, dataType uint32, relativeOffset uint32) {
+	checkThread()
+	gl.VertexArrayAttribIFormat(uint32(vao), uint32(attr), size, dataType, relativeOffset)
+}
+
+// AttribLFormat is like AttribFormat, but for double precision attributes (e.g. dvec3)
+// dataType must be gl.DOUBLE
+func (vao VertexArrayObject) AttribLFormat(attr VertexAttrib, size int32, dataType uint32, relativeOffset uint32) {
+	checkThread()
+	gl.VertexArrayAttribLFormat(uint32(vao), uint32(attr), size, dataType, relativeOffset)
+}
+
 func (vao VertexArrayObject) AttribFormat32(attr VertexAttrib, size int32, offset uint32) {
+	checkThread()
 	gl.VertexArrayAttribFormat(uint32(vao), uint32(attr), size, gl.FLOAT, false, offset*4)
 }
 
@@ -88,7 +120,21 @@ func (vao VertexArrayObject) AttribFormat32(attr VertexAttrib, size int32, offse
 // By using the same bindIndex in VertexBuffer, AttribFormat and AttribBinding,
 // the user can create a corrispondence between the attribute and the buffer.
 func (vao VertexArrayObject) AttribBinding(bindIndex uint32, attr VertexAttrib) {
+	checkThread()
 	gl.VertexArrayAttribBinding(uint32(vao), uint32(attr), bindIndex)
 }
 
-// TODO http://docs.gl/gl4/glVertexArrayElementBuffer
+// BindingDivisor sets how often the attributes bound to bindIndex advance
+// With divisor 0 (default), attributes advance once per vertex, with divisor
+// n they advance once every n instances when using instanced drawing
+func (vao VertexArrayObject) BindingDivisor(bindIndex, divisor uint32) {
+	checkThread()
+	gl.VertexArrayBindingDivisor(uint32(vao), bindIndex, divisor)
+}
+
+// ElementBuffer sets the buffer containing the indices used by DrawElements
+// when the VAO is bound
+func (vao VertexArrayObject) ElementBuffer(buffer VertexBufferObject) {
+	checkThread()
+	gl.VertexArrayElementBuffer(uint32(vao), uint32(buffer))
+}
@@ -0,0 +1,290 @@
+package glad
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"github.com/akiross/go-glad/glsl"
+	"github.com/go-gl/gl/v4.5-core/gl"
+)
+
+// Keyword is a feature of a shader that changes between variants
+// A boolean keyword is defined or not, e.g. SKINNING. An enum keyword takes
+// one of its values, defined as NAME_VALUE, e.g. SHADOWS_PCF: the first
+// value is the default, used when no value is requested
+type Keyword struct {
+	Name   string
+	Values []string // nil for a boolean keyword, at least one for an enum
+}
+
+// BoolKeyword returns a keyword that is defined or not
+func BoolKeyword(name string) Keyword {
+	return Keyword{Name: name}
+}
+
+// EnumKeyword returns a keyword taking one of the values
+func EnumKeyword(name string, values ...string) Keyword {
+	// Never nil, so that an enum without values is not taken for a boolean
+	return Keyword{Name: name, Values: append([]string{}, values...)}
+}
+
+// ShaderVariants compiles the same sources with different combinations of
+// keywords, passed to the shaders as defines. Programs are compiled when
+// first requested and cached, errors are cached too. Typical usage is
+//
+//	sv, err := glad.NewShaderVariants("mesh", map[uint32]string{
+//		gl.VERTEX_SHADER:   vertSrc,
+//		gl.FRAGMENT_SHADER: fragSrc,
+//	}, glad.BoolKeyword("SKINNING"), glad.EnumKeyword("SHADOWS", "OFF", "PCF"))
+//	sv.Version = "450 core"
+//	prog, err := sv.Get("SKINNING", "SHADOWS_PCF")
+//
+// Sources can #include files from FS, see the glsl package
+type ShaderVariants struct {
+	FS      fs.FS  // Files that can be included, nil if none
+	Version string // #version of the sources, if empty the one in the sources is used
+
+	name     string
+	sources  map[uint32]string // Source of each stage
+	keywords []Keyword
+	cache    map[string]*shaderVariant
+}
+
+type shaderVariant struct {
+	prog Program
+	err  error
+}
+
+// VariantError reports a variant that failed to compile or link
+type VariantError struct {
+	Defines []string // Defines of the variant
+	Err     error
+}
+
+func (e *VariantError) Error() string {
+	return e.Err.Error() // Already names the variant
+}
+
+// NewShaderVariants creates the variants of the sources of a program, one for
+// each stage. name is used for the labels of the programs and in errors
+// An error is returned if a keyword has no name, an enum has no values or
+// two keywords produce the same define
+func NewShaderVariants(name string, sources map[uint32]string, keywords ...Keyword) (*ShaderVariants, error) {
+	defines := make(map[string]string) // Keyword producing each define
+	for _, kw := range keywords {
+		if kw.Name == "" {
+			return nil, fmt.Errorf("%s: keyword without name", name)
+		}
+		if kw.Values != nil && len(kw.Values) == 0 {
+			return nil, fmt.Errorf("%s: enum keyword %s has no values", name, kw.Name)
+		}
+		defs := []string{kw.Name}
+		if kw.Values != nil {
+			defs = defs[:0]
+			for _, v := range kw.Values {
+				if v == "" {
+					return nil, fmt.Errorf("%s: enum keyword %s has an empty value", name, kw.Name)
+				}
+				defs = append(defs, kw.Name+"_"+v)
+			}
+		}
+		for _, d := range defs {
+			if other, ok := defines[d]; ok {
+				return nil, fmt.Errorf("%s: keywords %s and %s both define %s", name, other, kw.Name, d)
+			}
+			defines[d] = kw.Name
+		}
+	}
+	return &ShaderVariants{
+		name:     name,
+		sources:  sources,
+		keywords: append([]Keyword(nil), keywords...),
+		cache:    make(map[string]*shaderVariant),
+	}, nil
+}
+
+// Get returns the program of the variant with the given defines, compiling
+// it the first time. Each define is the name of a boolean keyword or the
+// NAME_VALUE of an enum keyword; enum keywords not listed take their default
+func (sv *ShaderVariants) Get(defines ...string) (Program, error) {
+	sel, err := sv.selection(defines)
+	if err != nil {
+		return 0, err
+	}
+	return sv.variant(sel)
+}
+
+// Variants returns the defines of all the combinations of keywords
+func (sv *ShaderVariants) Variants() [][]string {
+	var all [][]string
+	sel := make([]int, len(sv.keywords))
+	for {
+		all = append(all, sv.defines(sel))
+		// Next combination, like a counter where each digit is a keyword
+		i := 0
+		for ; i < len(sel); i++ {
+			sel[i]++
+			if sel[i] < sv.choices(i) {
+				break
+			}
+			sel[i] = 0
+		}
+		if i == len(sel) {
+			return all
+		}
+	}
+}
+
+// Precompile compiles all the variants not compiled yet, e.g. at startup to
+// avoid stalls later. It returns the number of variants available and the
+// ones that failed
+func (sv *ShaderVariants) Precompile() (compiled int, failed []*VariantError) {
+	for _, defs := range sv.Variants() {
+		sel, _ := sv.selection(defs)
+		if _, err := sv.variant(sel); err != nil {
+			failed = append(failed, &VariantError{Defines: defs, Err: err})
+			continue
+		}
+		compiled++
+	}
+	return compiled, failed
+}
+
+// Delete deletes the programs of all the variants
+func (sv *ShaderVariants) Delete() {
+	for _, v := range sv.cache {
+		if v.err == nil {
+			v.prog.Delete()
+		}
+	}
+	sv.cache = make(map[string]*shaderVariant)
+}
+
+// choices returns the number of values of the i-th keyword
+func (sv *ShaderVariants) choices(i int) int {
+	if sv.keywords[i].Values == nil {
+		return 2
+	}
+	return len(sv.keywords[i].Values)
+}
+
+// selection converts the defines to the index of the value of each keyword
+// For boolean keywords 1 means defined
+func (sv *ShaderVariants) selection(defines []string) ([]int, error) {
+	sel := make([]int, len(sv.keywords))
+	set := make([]bool, len(sv.keywords))
+	for _, d := range defines {
+		found := false
+		for i, kw := range sv.keywords {
+			v := -1
+			if kw.Values == nil {
+				if d == kw.Name {
+					v = 1
+				}
+			} else if strings.HasPrefix(d, kw.Name+"_") {
+				for j, val := range kw.Values {
+					if d == kw.Name+"_"+val {
+						v = j
+					}
+				}
+			}
+			if v < 0 {
+				continue
+			}
+			if set[i] && sel[i] != v {
+				return nil, fmt.Errorf("%s: conflicting values for keyword %s", sv.name, kw.Name)
+			}
+			sel[i], set[i], found = v, true, true
+			break
+		}
+		if !found {
+			return nil, fmt.Errorf("%s: unknown keyword %s", sv.name, d)
+		}
+	}
+	return sel, nil
+}
+
+// defines returns the defines of a selection
+func (sv *ShaderVariants) defines(sel []int) []string {
+	var defs []string
+	for i, kw := range sv.keywords {
+		switch {
+		case kw.Values != nil:
+			defs = append(defs, kw.Name+"_"+kw.Values[sel[i]])
+		case sel[i] == 1:
+			defs = append(defs, kw.Name)
+		}
+	}
+	return defs
+}
+
+// variant returns the program of a selection, compiling it if needed
+func (sv *ShaderVariants) variant(sel []int) (Program, error) {
+	defs := sv.defines(sel)
+	key := strings.Join(defs, " ")
+	if v, ok := sv.cache[key]; ok {
+		return v.prog, v.err
+	}
+	prog, err := sv.compile(defs)
+	if err != nil {
+		err = fmt.Errorf("%s [%s]: %v", sv.name, key, err)
+	} else {
+		prog.SetLabel(sv.name + " [" + key + "]")
+	}
+	sv.cache[key] = &shaderVariant{prog, err}
+	return prog, err
+}
+
+// compile builds the program with the defines
+func (sv *ShaderVariants) compile(defs []string) (Program, error) {
+	opts := &glsl.Options{Version: sv.Version, Defines: make(map[string]string, len(defs))}
+	for _, d := range defs {
+		opts.Defines[d] = ""
+	}
+	stages := make([]uint32, 0, len(sv.sources))
+	for st := range sv.sources {
+		stages = append(stages, st)
+	}
+	sort.Slice(stages, func(i, j int) bool { return stages[i] < stages[j] })
+
+	var shaders []Shader
+	defer func() { deleteShaders(shaders) }()
+	for _, st := range stages {
+		file := sv.name + stageExt(st)
+		src, err := glsl.PreprocessString(sv.FS, file, sv.sources[st], opts)
+		if err != nil {
+			return 0, err
+		}
+		sh, err := CompileShader(src.Text, st)
+		if err != nil {
+			if se, ok := err.(*ShaderError); ok {
+				se.File = file
+				se.Log = src.TranslateLog(se.Log)
+			}
+			return 0, err
+		}
+		shaders = append(shaders, sh)
+	}
+	return LinkProgram(shaders...)
+}
+
+// stageExt returns the usual file extension of a stage, used to name sources
+func stageExt(stage uint32) string {
+	switch stage {
+	case gl.VERTEX_SHADER:
+		return ".vert"
+	case gl.FRAGMENT_SHADER:
+		return ".frag"
+	case gl.GEOMETRY_SHADER:
+		return ".geom"
+	case gl.TESS_CONTROL_SHADER:
+		return ".tesc"
+	case gl.TESS_EVALUATION_SHADER:
+		return ".tese"
+	case gl.COMPUTE_SHADER:
+		return ".comp"
+	}
+	return ".glsl"
+}
@@ -0,0 +1,114 @@
+package glad
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func newTestVariants(t *testing.T) *ShaderVariants {
+	t.Helper()
+	sv, err := NewShaderVariants("mesh", nil,
+		BoolKeyword("SKINNING"), EnumKeyword("SHADOWS", "OFF", "PCF", "VSM"), BoolKeyword("FOG"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sv
+}
+
+func TestVariantsSelection(t *testing.T) {
+	sv := newTestVariants(t)
+	tests := []struct {
+		defines []string
+		want    string // Defines of the selected variant
+		err     string
+	}{
+		{nil, "[SHADOWS_OFF]", ""},
+		{[]string{"SKINNING"}, "[SKINNING SHADOWS_OFF]", ""},
+		{[]string{"FOG", "SHADOWS_PCF"}, "[SHADOWS_PCF FOG]", ""},
+		{[]string{"SHADOWS_VSM", "SKINNING", "FOG"}, "[SKINNING SHADOWS_VSM FOG]", ""},
+		{[]string{"SKINNING", "SKINNING"}, "[SKINNING SHADOWS_OFF]", ""},
+		{[]string{"SHADOWS_PCF", "SHADOWS_PCF"}, "[SHADOWS_PCF]", ""},
+		{[]string{"SHADOWS_PCF", "SHADOWS_VSM"}, "", "mesh: conflicting values for keyword SHADOWS"},
+		{[]string{"SHADOWS_ON"}, "", "mesh: unknown keyword SHADOWS_ON"},
+		{[]string{"SHADOWS"}, "", "mesh: unknown keyword SHADOWS"},
+		{[]string{"NORMALS"}, "", "mesh: unknown keyword NORMALS"},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.defines, ","), func(t *testing.T) {
+			sel, err := sv.selection(tt.defines)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Errorf("got error %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := fmt.Sprint(sv.defines(sel)); got != tt.want {
+				t.Errorf("got defines %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVariantsList(t *testing.T) {
+	sv := newTestVariants(t)
+	all := sv.Variants()
+	if len(all) != 2*3*2 {
+		t.Fatalf("got %d variants, want 12", len(all))
+	}
+	seen := make(map[string]bool)
+	for _, defs := range all {
+		key := fmt.Sprint(defs)
+		if seen[key] {
+			t.Errorf("variant %s listed twice", key)
+		}
+		seen[key] = true
+		// Each variant must select itself
+		sel, err := sv.selection(defs)
+		if err != nil {
+			t.Fatalf("variant %s: %v", key, err)
+		}
+		if got := fmt.Sprint(sv.defines(sel)); got != key {
+			t.Errorf("variant %s selects %s", key, got)
+		}
+	}
+	if first := fmt.Sprint(all[0]); first != "[SHADOWS_OFF]" {
+		t.Errorf("first variant is %s, want the default", first)
+	}
+
+	none, err := NewShaderVariants("plain", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(none.Variants()); got != "[[]]" {
+		t.Errorf("without keywords got variants %s, want a single one", got)
+	}
+}
+
+func TestVariantsKeywords(t *testing.T) {
+	tests := []struct {
+		name     string
+		keywords []Keyword
+		err      string
+	}{
+		{"NoName", []Keyword{BoolKeyword("")}, "mesh: keyword without name"},
+		{"EnumNoValues", []Keyword{EnumKeyword("SHADOWS")}, "mesh: enum keyword SHADOWS has no values"},
+		{"EmptyValues", []Keyword{{Name: "SHADOWS", Values: []string{}}}, "mesh: enum keyword SHADOWS has no values"},
+		{"EmptyValue", []Keyword{EnumKeyword("SHADOWS", "OFF", "")}, "mesh: enum keyword SHADOWS has an empty value"},
+		{"DuplicateBool", []Keyword{BoolKeyword("FOG"), BoolKeyword("FOG")}, "mesh: keywords FOG and FOG both define FOG"},
+		{"DuplicateValue", []Keyword{EnumKeyword("SHADOWS", "PCF", "PCF")}, "mesh: keywords SHADOWS and SHADOWS both define SHADOWS_PCF"},
+		{"Overlap", []Keyword{EnumKeyword("SHADOWS", "PCF"), BoolKeyword("SHADOWS_PCF")}, "mesh: keywords SHADOWS and SHADOWS_PCF both define SHADOWS_PCF"},
+		{"Valid", []Keyword{EnumKeyword("SHADOWS", "OFF"), BoolKeyword("SHADOWS")}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewShaderVariants("mesh", nil, tt.keywords...)
+			if tt.err == "" && err != nil || tt.err != "" && (err == nil || err.Error() != tt.err) {
+				t.Errorf("got error %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -11,8 +11,10 @@ type VertexBufferObject uint32
 
 // NewVertexBufferObject creates a new buffer object
 func NewVertexBufferObject() VertexBufferObject {
+	checkThread()
 	var vbo uint32
 	gl.CreateBuffers(1, &vbo)
+	registry.track("VertexBufferObject", vbo)
 	return VertexBufferObject(vbo)
 }
 
@@ -20,8 +22,11 @@ func NewVertexBufferObject() VertexBufferObject {
 
 // Delete the VBO freeing its name
 func (vbo VertexBufferObject) Delete() {
+	checkThread()
 	v := uint32(vbo)
 	gl.DeleteBuffers(1, &v)
+	stateCache().forget(bindBuffer, v)
+	registry.untrack("VertexBufferObject", v)
 }
 
 // Bind the VBO to the specified target
@@ -33,31 +38,45 @@ func (vbo VertexBufferObject) Delete() {
 // - gl.COPY_READ_BUFFER and gl.COPY_WRITE_BUFFER two buffers that can be used
 //                                                to copy data between buffers
 // - gl.DRAW_INDIRECT_BUFFER to store parameters when performing indirect drawing
+//                           (see IndirectBuffer)
+// - gl.PARAMETER_BUFFER_ARB to store the number of indirect draws to perform
 // TODO doc: add and explain other targets
 func (vbo VertexBufferObject) Bind(target uint32) {
-	gl.BindBuffer(target, uint32(vbo))
+	checkThread()
+	if stateCache().bind(bindBuffer, target, uint32(vbo)) {
+		gl.BindBuffer(target, uint32(vbo))
+	}
 }
 
 // Unbind the VBO from the specified target
 func (vbo VertexBufferObject) Unbind(target uint32) {
-	gl.BindBuffer(target, 0)
+	checkThread()
+	if stateCache().bind(bindBuffer, target, 0) {
+		gl.BindBuffer(target, 0)
+	}
 }
 
 // BufferStorage allocates a new immutable data store for the VBO
 // The storage cannot change in size: to do so, the VBO must be deleted first
 // and created again with a new size. Data can be modified using BufferSubData
 func (vbo VertexBufferObject) BufferStorage(data []float32, flags uint32) {
+	checkThread()
 	gl.NamedBufferStorage(uint32(vbo), len(data)*4, gl.Ptr(data), flags)
+	registry.resize("VertexBufferObject", uint32(vbo), len(data)*4)
 }
 
 // BufferStorage32 allocates the storage for the VBO and copies float32 data in it
 func (vbo VertexBufferObject) BufferStorage32(data []float32, flags uint32) {
+	checkThread()
 	gl.NamedBufferStorage(uint32(vbo), len(data)*4, gl.Ptr(data), flags)
+	registry.resize("VertexBufferObject", uint32(vbo), len(data)*4)
 }
 
 // BufferStorage64 allocates the storage for the VBO and copies float64 data in it
 func (vbo VertexBufferObject) BufferStorage64(data []float64, flags uint32) {
+	checkThread()
 	gl.NamedBufferStorage(uint32(vbo), len(data)*8, gl.Ptr(data), flags)
+	registry.resize("VertexBufferObject", uint32(vbo), len(data)*8)
 }
 
 // BufferData32 allocates a new data store for float32 data in the VBO
@@ -66,25 +85,40 @@ func (vbo VertexBufferObject) BufferStorage64(data []float64, flags uint32) {
 // - nature: DRAW, READ, COPY
 // Pre-existing storage will be deleted, therefore size might change
 func (vbo VertexBufferObject) BufferData32(data []float32, usage uint32) {
+	checkThread()
 	gl.NamedBufferData(uint32(vbo), len(data)*4, gl.Ptr(data), usage)
+	registry.resize("VertexBufferObject", uint32(vbo), len(data)*4)
+}
+
+// BufferData16 allocates a new data store for int16 data in the VBO
+// This is used for element indices, see Config.Elements
+func (vbo VertexBufferObject) BufferData16(data []int16, usage uint32) {
+	checkThread()
+	gl.NamedBufferData(uint32(vbo), len(data)*2, gl.Ptr(data), usage)
+	registry.resize("VertexBufferObject", uint32(vbo), len(data)*2)
 }
 
 // BufferData64 is the same of BufferData32 but with float32
 func (vbo VertexBufferObject) BufferData64(data []float64, usage uint32) {
+	checkThread()
 	gl.NamedBufferData(uint32(vbo), len(data)*8, gl.Ptr(data), usage)
+	registry.resize("VertexBufferObject", uint32(vbo), len(data)*8)
 }
 
 // BufferSubData32 replaces part of the buffer content with new float32 data
 func (vbo VertexBufferObject) BufferSubData32(data []float32, offset int) {
+	checkThread()
 	gl.NamedBufferSubData(uint32(vbo), offset, len(data)*4, gl.Ptr(data))
 }
 
 // BufferSubData64 replaces part of the buffer content with new float64 data
 func (vbo VertexBufferObject) BufferSubData64(data []float64, offset int) {
+	checkThread()
 	gl.NamedBufferSubData(uint32(vbo), offset, len(data)*8, gl.Ptr(data))
 }
 
 func (vbo VertexBufferObject) Clear32(data []float32) {
+	checkThread()
 	switch len(data) {
 	case 1:
 		gl.ClearNamedBufferData(uint32(vbo), gl.R32F, gl.RED, gl.FLOAT, gl.Ptr(data))
@@ -100,6 +134,7 @@ func (vbo VertexBufferObject) Clear32(data []float32) {
 }
 
 func (vbo VertexBufferObject) CopyTo(dest VertexBufferObject, readOffset, writeOffset int, size int32) {
+	checkThread()
 	gl.CopyNamedBufferSubData(uint32(vbo), uint32(dest), readOffset, writeOffset, int(size))
 }
 
@@ -1,13 +1,27 @@
 package glad
 
 import (
+	"fmt"
 	"log"
+	"time"
 
 	"github.com/go-gl/gl/v4.5-core/gl"
 	"github.com/go-gl/glfw/v3.2/glfw"
 )
 
-type WinOption func()
+// WinOption configures the creation of a window, see NewWindow
+type WinOption func(*winConfig)
+
+// winConfig collects what the options need besides the window hints
+type winConfig struct {
+	monitor *glfw.Monitor   // Monitor for fullscreen windows
+	mode    *glfw.VidMode   // Video mode for fullscreen windows
+	share   *glfw.Window    // Window to share objects with
+	after   []func(*Window) // Applied when the context is current
+}
+
+// contextNoError is GLFW_CONTEXT_NO_ERROR, not exported by the bindings
+const contextNoError glfw.Hint = 0x0002200A
 
 func glfwTF(v bool) int {
 	if v {
@@ -16,69 +30,252 @@ func glfwTF(v bool) int {
 	return glfw.False
 }
 
-func Resizable(v bool) WinOption {
-	return func() {
-		glfw.WindowHint(glfw.Resizable, glfwTF(v))
+// hint returns an option setting a window hint
+func hint(h glfw.Hint, v int) WinOption {
+	return func(*winConfig) {
+		glfw.WindowHint(h, v)
 	}
 }
 
+func Resizable(v bool) WinOption {
+	return hint(glfw.Resizable, glfwTF(v))
+}
+
 func ContextVersion(maj, min int) WinOption {
-	return func() {
+	return func(*winConfig) {
 		glfw.WindowHint(glfw.ContextVersionMajor, maj)
 		glfw.WindowHint(glfw.ContextVersionMinor, min)
 	}
 }
 
 func ForwardCompatible(v bool) WinOption {
-	return func() {
-		glfw.WindowHint(glfw.OpenGLForwardCompatible, glfwTF(v))
-	}
+	return hint(glfw.OpenGLForwardCompatible, glfwTF(v))
 }
 
 func CoreProfile(v bool) WinOption {
-	return func() {
-		if v {
-			glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
-		} else {
-			glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCompatProfile)
-		}
+	if v {
+		return hint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
 	}
+	return hint(glfw.OpenGLProfile, glfw.OpenGLCompatProfile)
 }
 
 func Decorated(v bool) WinOption {
-	return func() {
-		glfw.WindowHint(glfw.Decorated, glfwTF(v))
-	}
+	return hint(glfw.Decorated, glfwTF(v))
 }
 
+// VSync synchronizes buffer swaps with the refresh of the monitor
+// It is applied once the context has been created
 func VSync(v bool) WinOption {
-	return func() {
-		return // FIXME
-		if v {
-			glfw.SwapInterval(1)
-		} else {
-			glfw.SwapInterval(0)
+	return func(c *winConfig) {
+		c.after = append(c.after, func(*Window) {
+			if v {
+				glfw.SwapInterval(1)
+			} else {
+				glfw.SwapInterval(0)
+			}
+		})
+	}
+}
+
+// Samples sets the number of samples of the default framebuffer for
+// multisample antialiasing, 0 disables it
+func Samples(n int) WinOption {
+	return hint(glfw.Samples, n)
+}
+
+// SRGB requests a default framebuffer able to convert linear colors to sRGB
+// when gl.FRAMEBUFFER_SRGB is enabled
+func SRGB(v bool) WinOption {
+	return hint(glfw.SRGBCapable, glfwTF(v))
+}
+
+// DebugContext requests a context with additional error and performance
+// reporting, useful with debug labels and groups
+func DebugContext(v bool) WinOption {
+	return hint(glfw.OpenGLDebugContext, glfwTF(v))
+}
+
+// NoErrorContext requests a context that does not report errors, which can
+// be faster. Errors cause undefined behavior instead
+func NoErrorContext(v bool) WinOption {
+	return hint(contextNoError, glfwTF(v))
+}
+
+// Visible sets whether the window is shown when created
+// A hidden window can be shown later with Show
+func Visible(v bool) WinOption {
+	return hint(glfw.Visible, glfwTF(v))
+}
+
+// Floating keeps the window above the other windows
+func Floating(v bool) WinOption {
+	return hint(glfw.Floating, glfwTF(v))
+}
+
+// Maximized creates the window maximized
+func Maximized(v bool) WinOption {
+	return hint(glfw.Maximized, glfwTF(v))
+}
+
+// Fullscreen creates the window fullscreen on the monitor, nil for the
+// primary monitor, switching to the video mode, nil to keep the current one
+// The size of the mode replaces the size passed to NewWindow. Use
+// ClosestVideoMode to pick a mode among the available ones
+func Fullscreen(monitor *glfw.Monitor, mode *glfw.VidMode) WinOption {
+	return func(c *winConfig) {
+		if monitor == nil {
+			monitor = glfw.GetPrimaryMonitor()
+		}
+		if mode == nil {
+			mode = monitor.GetVideoMode()
 		}
+		glfw.WindowHint(glfw.RedBits, mode.RedBits)
+		glfw.WindowHint(glfw.GreenBits, mode.GreenBits)
+		glfw.WindowHint(glfw.BlueBits, mode.BlueBits)
+		glfw.WindowHint(glfw.RefreshRate, mode.RefreshRate)
+		c.monitor, c.mode = monitor, mode
 	}
 }
 
-func NewOGLWindow(width, height int, title string, opts ...WinOption) *glfw.Window {
-	// Initialize OpenGL
+// ClosestVideoMode returns the video mode of the monitor (nil for the primary
+// monitor) closest to the given size and refresh rate (0 for any)
+func ClosestVideoMode(monitor *glfw.Monitor, width, height, refreshRate int) *glfw.VidMode {
+	if monitor == nil {
+		monitor = glfw.GetPrimaryMonitor()
+	}
+	var best *glfw.VidMode
+	bestScore := -1
+	for _, m := range monitor.GetVideoModes() {
+		score := abs(m.Width-width) + abs(m.Height-height)
+		if refreshRate > 0 {
+			score += abs(m.RefreshRate - refreshRate)
+		}
+		if best == nil || score < bestScore {
+			best, bestScore = m, score
+		}
+	}
+	return best
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// SharedWith creates the context sharing objects (textures, buffers, ...)
+// with the context of another window
+func SharedWith(other *Window) WinOption {
+	return func(c *winConfig) {
+		c.share = other.Window
+	}
+}
+
+// Window is a GLFW window owning an OpenGL context
+// All the methods of glfw.Window are available, plus some shorthands
+type Window struct {
+	*glfw.Window
+	input *Input // Created by Input
+}
+
+// NewWindow creates a window with an OpenGL context, makes the context current
+// and loads the GL functions. Options are applied on top of the default GLFW
+// hints, so a failed attempt does not affect the next one: for instance, it
+// is possible to retry with a lower ContextVersion
+func NewWindow(width, height int, title string, opts ...WinOption) (*Window, error) {
 	if err := glfw.Init(); err != nil {
-		log.Fatalln("Failed to initialize GLFW", err)
+		return nil, fmt.Errorf("failed to initialize GLFW: %v", err)
 	}
+	glfw.DefaultWindowHints()
+	var cfg winConfig
 	for _, opt := range opts {
-		opt()
+		opt(&cfg)
 	}
-	win, err := glfw.CreateWindow(width, height, title, nil, nil)
+	if cfg.mode != nil {
+		width, height = cfg.mode.Width, cfg.mode.Height
+	}
+	gwin, err := glfw.CreateWindow(width, height, title, cfg.monitor, cfg.share)
 	if err != nil {
-		log.Fatalln("Failed to create window", err)
+		return nil, fmt.Errorf("failed to create window: %v", err)
 	}
-	win.MakeContextCurrent()
+	gwin.MakeContextCurrent()
 	if err := gl.Init(); err != nil {
-		log.Fatalln("Failed to initialize OpenGL", err)
+		gwin.Destroy()
+		return nil, fmt.Errorf("failed to initialize OpenGL: %v", err)
+	}
+	win := &Window{Window: gwin}
+	for _, fn := range cfg.after {
+		fn(win)
+	}
+	return win, nil
+}
+
+// NewOGLWindow is like NewWindow, but exits the program on errors and returns
+// the GLFW window
+//
+// Deprecated: use NewWindow
+func NewOGLWindow(width, height int, title string, opts ...WinOption) *glfw.Window {
+	win, err := NewWindow(width, height, title, opts...)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	return win.Window
+}
+
+// Destroy destroys the window and its context
+func (w *Window) Destroy() {
+	forgetCaps(w.Window)
+	setStateCache(w.Window, nil)
+	w.Window.Destroy()
+}
+
+// Size returns the size of the window in screen coordinates
+func (w *Window) Size() (width, height int) {
+	return w.GetSize()
+}
+
+// FramebufferSize returns the size of the framebuffer in pixels, to be used
+// with gl.Viewport. It can differ from Size on high-DPI displays
+func (w *Window) FramebufferSize() (width, height int) {
+	return w.GetFramebufferSize()
+}
+
+// MakeCurrent makes the context of the window current in the calling thread
+func (w *Window) MakeCurrent() {
+	w.MakeContextCurrent()
+}
+
+// Close flags the window to be closed, ShouldClose will return true
+func (w *Window) Close() {
+	w.SetShouldClose(true)
+}
+
+// Swap swaps the front and back buffers, showing what has been drawn
+func (w *Window) Swap() {
+	w.SwapBuffers()
+}
+
+// PollEvents processes the pending events of all the windows, calling the
+// callbacks, and returns immediately. The Input of the window starts a new frame
+func (w *Window) PollEvents() {
+	if w.input != nil {
+		w.input.newFrame()
+	}
+	glfw.PollEvents()
+}
+
+// WaitEvents waits until an event is received or the timeout expires (0 to
+// wait indefinitely), then processes the events like PollEvents
+func (w *Window) WaitEvents(timeout time.Duration) {
+	if w.input != nil {
+		w.input.newFrame()
+	}
+	if timeout > 0 {
+		glfw.WaitEventsTimeout(timeout.Seconds())
+	} else {
+		glfw.WaitEvents()
 	}
-	return win
 }
 
 var (